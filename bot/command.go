@@ -0,0 +1,91 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"stargazers/gh"
+	"stargazers/trending"
+)
+
+const usage = `Commands, followed by an optional repo to filter:
+stars - the current stars
+today - the stars today
+compare - the stars against the comparisons
+trending - the positions in trending
+kol - the stargazers with the most followers`
+
+// answer answers the command from the stats of the monitors and the trending positions.
+func answer(command string, stats []gh.RepoStats, positions []trending.Position) string {
+	fields := strings.Fields(strings.ToLower(command))
+	if len(fields) == 0 {
+		fields = []string{"stars"}
+	}
+	if len(fields) > 1 {
+		stats = filter(stats, fields[1])
+	}
+
+	var lines []string
+	switch fields[0] {
+	case "stars":
+		for _, rs := range stats {
+			lines = append(lines, fmt.Sprintf("%s: %d stars", rs.Repo, rs.Stars))
+		}
+	case "today":
+		for _, rs := range stats {
+			lines = append(lines, fmt.Sprintf("%s: %d stars today", rs.Repo, rs.Today))
+		}
+	case "compare":
+		for _, rs := range stats {
+			for _, comp := range rs.Comparisons {
+				lines = append(lines, fmt.Sprintf("%s: %d, %s: %d, %+d",
+					rs.Repo, rs.Stars, comp.Repo, comp.Stars, rs.Stars-comp.Stars))
+			}
+		}
+		if len(lines) == 0 {
+			return "No comparisons."
+		}
+	case "trending":
+		for _, pos := range positions {
+			name := pos.Range + " trending"
+			if pos.Lang != "" {
+				name = pos.Lang + " " + name
+			}
+			lines = append(lines, fmt.Sprintf("%s: #%d", name, pos.Pos))
+		}
+		if len(lines) == 0 {
+			return "Not in trending."
+		}
+	case "kol", "top":
+		for _, rs := range stats {
+			for _, kol := range rs.Kols {
+				lines = append(lines, fmt.Sprintf("%s: %s, %d followers",
+					rs.Repo, kol.Login, kol.Followers))
+			}
+		}
+		if len(lines) == 0 {
+			return "No stargazers since started."
+		}
+	default:
+		return usage
+	}
+
+	if len(lines) == 0 {
+		return "No stats yet, the repos are still loading."
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// filter keeps the repos matching the full name or the name.
+func filter(stats []gh.RepoStats, repo string) []gh.RepoStats {
+	var matched []gh.RepoStats
+	for _, rs := range stats {
+		full := strings.ToLower(rs.Repo)
+		if full == repo || strings.HasSuffix(full, "/"+repo) {
+			matched = append(matched, rs)
+		}
+	}
+
+	return matched
+}
@@ -0,0 +1,41 @@
+package bot
+
+import (
+	"testing"
+
+	"stargazers/gh"
+	"stargazers/trending"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnswer(t *testing.T) {
+	stats := []gh.RepoStats{
+		{
+			Repo:  "zeromicro/go-zero",
+			Stars: 30000,
+			Today: 12,
+			Comparisons: []gh.Comparison{
+				{Repo: "go-kratos/kratos", Stars: 23000},
+			},
+			Kols: []gh.Kol{
+				{Login: "kevwan", Followers: 5000},
+			},
+		},
+		{
+			Repo:  "zeromicro/go-queue",
+			Stars: 1500,
+		},
+	}
+	positions := []trending.Position{
+		{Lang: "Go", Range: "daily", Pos: 3},
+	}
+
+	assert.Equal(t, "zeromicro/go-zero: 30000 stars\nzeromicro/go-queue: 1500 stars", answer("", stats, positions))
+	assert.Equal(t, "zeromicro/go-zero: 12 stars today", answer("Today go-zero", stats, positions))
+	assert.Equal(t, "zeromicro/go-zero: 30000, go-kratos/kratos: 23000, +7000", answer("compare", stats, positions))
+	assert.Equal(t, "Go daily trending: #3", answer("trending", stats, positions))
+	assert.Equal(t, "zeromicro/go-zero: kevwan, 5000 followers", answer("kol", stats, positions))
+	assert.Equal(t, "No stats yet, the repos are still loading.", answer("stars go-kit", stats, positions))
+	assert.Equal(t, usage, answer("help", stats, positions))
+}
@@ -0,0 +1,31 @@
+package bot
+
+type (
+	Bot struct {
+		Host  string `json:"host,default=0.0.0.0"`
+		Port  int    `json:"port,default=8080"`
+		Slack *Slack `json:"slack,optional"`
+		Lark  *Lark  `json:"lark,optional"`
+		Wecom *Wecom `json:"wecom,optional"`
+	}
+
+	// Slack handles the slash commands on /slack.
+	Slack struct {
+		SigningSecret string `json:"signingSecret"`
+	}
+
+	// Lark handles the message events of the app bot on /lark, replied with the lark app.
+	Lark struct {
+		VerificationToken string `json:"verificationToken"`
+		// EncryptKey decrypts the events and verifies their signatures.
+		EncryptKey string `json:"encryptKey,optional"`
+	}
+
+	// Wecom handles the messages of the corp application on /wecom, replied with the wecom app.
+	Wecom struct {
+		Token          string `json:"token"`
+		EncodingAESKey string `json:"encodingAESKey"`
+		// CorpId is checked against the messages, defaults to the corpId of the wecom app.
+		CorpId string `json:"corpId,optional"`
+	}
+)
@@ -0,0 +1,30 @@
+package bot
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+)
+
+var errInvalidCiphertext = errors.New("bot: invalid ciphertext")
+
+// decryptCBC decrypts the AES-CBC data and removes the PKCS#7 padding of blockSize.
+func decryptCBC(key, iv, data []byte, blockSize int) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, errInvalidCiphertext
+	}
+
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data)
+
+	pad := int(plain[len(plain)-1])
+	if pad == 0 || pad > blockSize || pad > len(plain) {
+		return nil, errInvalidCiphertext
+	}
+
+	return plain[:len(plain)-pad], nil
+}
@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	larkVerification  = "url_verification"
+	larkMessageEvent  = "im.message.receive_v1"
+	larkTextMessage   = "text"
+	larkMentionPrefix = "@_user_"
+)
+
+type (
+	larkHandler struct {
+		c       *Lark
+		replier Replier
+	}
+
+	larkEncrypted struct {
+		Encrypt string `json:"encrypt"`
+	}
+
+	// https://open.feishu.cn/document/server-docs/event-subscription-guide/event-subscription-configure-/request-url-configuration-case
+	larkEvent struct {
+		Type      string `json:"type"`
+		Token     string `json:"token"`
+		Challenge string `json:"challenge"`
+		Header    struct {
+			EventType string `json:"event_type"`
+			Token     string `json:"token"`
+		} `json:"header"`
+		Event struct {
+			Message struct {
+				ChatId      string `json:"chat_id"`
+				MessageType string `json:"message_type"`
+				Content     string `json:"content"`
+			} `json:"message"`
+		} `json:"event"`
+	}
+
+	larkText struct {
+		Text string `json:"text"`
+	}
+)
+
+// ServeHTTP handles the url verification and the messages to the bot,
+// the answers are replied to the chats afterwards.
+func (h *larkHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := readBody(w, r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	payload := body
+	if len(h.c.EncryptKey) > 0 {
+		var encrypted larkEncrypted
+		if err := json.Unmarshal(body, &encrypted); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if payload, err = decryptLark(h.c.EncryptKey, encrypted.Encrypt); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	var event larkEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// the url verification is not signed, but carries the token
+	if event.Type == larkVerification {
+		if !h.verifyToken(event.Token) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]string{
+			"challenge": event.Challenge,
+		}); err != nil {
+			logx.Error(err)
+		}
+		return
+	}
+
+	if !h.verifyToken(event.Header.Token) || len(h.c.EncryptKey) > 0 && !verifyLark(h.c.EncryptKey,
+		r.Header.Get("X-Lark-Request-Timestamp"), r.Header.Get("X-Lark-Request-Nonce"),
+		r.Header.Get("X-Lark-Signature"), body, time.Now()) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	msg := event.Event.Message
+	if event.Header.EventType != larkMessageEvent || msg.MessageType != larkTextMessage {
+		return
+	}
+
+	var text larkText
+	if err := json.Unmarshal([]byte(msg.Content), &text); err != nil {
+		logx.Error(err)
+		return
+	}
+
+	reply(h.replier, msg.ChatId, stripMentions(text.Text))
+}
+
+// verifyToken checks the token if set, the events are verified by the signatures otherwise.
+func (h *larkHandler) verifyToken(token string) bool {
+	if len(h.c.VerificationToken) == 0 {
+		return len(h.c.EncryptKey) > 0
+	}
+
+	return subtle.ConstantTimeCompare([]byte(h.c.VerificationToken), []byte(token)) == 1
+}
+
+// decryptLark decrypts with the sha256 of the key, the iv is the first block of the data.
+func decryptLark(key, encrypted string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 16 {
+		return nil, errInvalidCiphertext
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	return decryptCBC(sum[:], data[:16], data[16:], 16)
+}
+
+func verifyLark(key, timestamp, nonce, signature string, body []byte, now time.Time) bool {
+	if !fresh(timestamp, now) {
+		return false
+	}
+
+	h := sha256.New()
+	h.Write([]byte(timestamp + nonce + key))
+	h.Write(body)
+	expected := hex.EncodeToString(h.Sum(nil))
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) == 1
+}
+
+// stripMentions removes the mentions of the bot in groups, like @_user_1.
+func stripMentions(text string) string {
+	var fields []string
+	for _, field := range strings.Fields(text) {
+		if !strings.HasPrefix(field, larkMentionPrefix) {
+			fields = append(fields, field)
+		}
+	}
+
+	return strings.Join(fields, " ")
+}
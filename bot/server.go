@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"stargazers/gh"
+	"stargazers/trending"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+const (
+	maxBodySize       = 1 << 20
+	readHeaderTimeout = time.Second * 5
+	readTimeout       = time.Second * 10
+	writeTimeout      = time.Second * 10
+	replyTimeout      = time.Second * 30
+	shutdownTimeout   = time.Second * 5
+	// maxRequestAge rejects the replayed requests, like Slack suggests in
+	// https://api.slack.com/authentication/verifying-requests-from-slack
+	maxRequestAge = time.Minute * 5
+)
+
+type (
+	// Replier sends the answers back to the chats of Lark and Wecom,
+	// which don't take the answers in the responses.
+	Replier interface {
+		Reply(ctx context.Context, to, text string) error
+	}
+
+	// Server answers the bot commands from the in-memory state of the monitors.
+	Server struct {
+		srv *http.Server
+	}
+)
+
+func NewServer(c Bot, lark, wecom Replier) (*Server, error) {
+	mux := http.NewServeMux()
+	if c.Slack != nil {
+		mux.Handle("/slack", &slackHandler{c: c.Slack})
+	}
+	if c.Lark != nil {
+		if len(c.Lark.VerificationToken) == 0 && len(c.Lark.EncryptKey) == 0 {
+			return nil, errors.New("bot: lark verificationToken or encryptKey is required")
+		}
+		if lark == nil {
+			return nil, errors.New("bot: lark app is required to reply to lark")
+		}
+		mux.Handle("/lark", &larkHandler{c: c.Lark, replier: lark})
+	}
+	if c.Wecom != nil {
+		if wecom == nil {
+			return nil, errors.New("bot: wecom app is required to reply to wecom")
+		}
+		h, err := newWecomHandler(c.Wecom, wecom)
+		if err != nil {
+			return nil, err
+		}
+		mux.Handle("/wecom", h)
+	}
+
+	return &Server{
+		srv: &http.Server{
+			Addr:              fmt.Sprintf("%s:%d", c.Host, c.Port),
+			Handler:           mux,
+			ReadHeaderTimeout: readHeaderTimeout,
+			ReadTimeout:       readTimeout,
+			WriteTimeout:      writeTimeout,
+		},
+	}, nil
+}
+
+func (s *Server) Start() {
+	if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logx.Error(err)
+	}
+}
+
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := s.srv.Shutdown(ctx); err != nil {
+		logx.Error(err)
+	}
+}
+
+// fresh tells whether the timestamp in seconds is within maxRequestAge of now.
+func fresh(timestamp string, now time.Time) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	age := now.Sub(time.Unix(ts, 0))
+	return age <= maxRequestAge && age >= -maxRequestAge
+}
+
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	return io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+}
+
+// reply answers the command in background, since the platforms expect the response in seconds.
+func reply(replier Replier, to, command string) {
+	text := answer(command, gh.Stats(), trending.Positions())
+	threading.GoSafe(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), replyTimeout)
+		defer cancel()
+
+		if err := replier.Reply(ctx, to, text); err != nil {
+			logx.Errorf("bot: failed to reply to %s, %v", to, err)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"stargazers/gh"
+	"stargazers/trending"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type (
+	slackHandler struct {
+		c *Slack
+	}
+
+	slackResponse struct {
+		ResponseType string `json:"response_type"`
+		Text         string `json:"text"`
+	}
+)
+
+// ServeHTTP answers the slash commands in the response, the command name is not in the text.
+func (h *slackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := readBody(w, r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if !verifySlack(h.c.SigningSecret, r.Header.Get("X-Slack-Request-Timestamp"),
+		r.Header.Get("X-Slack-Signature"), body, time.Now()) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(slackResponse{
+		ResponseType: "in_channel",
+		Text:         answer(form.Get("text"), gh.Stats(), trending.Positions()),
+	}); err != nil {
+		logx.Error(err)
+	}
+}
+
+func verifySlack(secret, timestamp, signature string, body []byte, now time.Time) bool {
+	if !fresh(timestamp, now) {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
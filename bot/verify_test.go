@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySlack(t *testing.T) {
+	body := []byte("command=%2Fstars&text=today")
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("v0:1700000000:"))
+	mac.Write(body)
+	signature := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	now := time.Unix(1700000060, 0)
+	assert.True(t, verifySlack("secret", "1700000000", signature, body, now))
+	assert.False(t, verifySlack("other", "1700000000", signature, body, now))
+	assert.False(t, verifySlack("secret", "1700000000", signature, body, now.Add(time.Hour)))
+}
+
+func TestDecryptLark(t *testing.T) {
+	sum := sha256.Sum256([]byte("key"))
+	iv := bytes.Repeat([]byte{1}, aes.BlockSize)
+	data := append(iv, encryptCBC(t, sum[:], iv, []byte(`{"type":"url_verification"}`), aes.BlockSize)...)
+
+	plain, err := decryptLark("key", base64.StdEncoding.EncodeToString(data))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"url_verification"}`, string(plain))
+
+	_, err = decryptLark("key", base64.StdEncoding.EncodeToString(iv))
+	assert.Error(t, err)
+}
+
+func TestDecryptWecom(t *testing.T) {
+	key := bytes.Repeat([]byte("k"), wecomKeySize)
+	h, err := newWecomHandler(&Wecom{
+		Token:          "token",
+		EncodingAESKey: base64.StdEncoding.EncodeToString(key)[:43],
+		CorpId:         "corpid",
+	}, nil)
+	assert.NoError(t, err)
+
+	msg := []byte("<xml><Content>stars</Content></xml>")
+	plain := append(bytes.Repeat([]byte("r"), wecomRandomSize), binary.BigEndian.AppendUint32(nil, uint32(len(msg)))...)
+	plain = append(append(plain, msg...), "corpid"...)
+	encrypted := base64.StdEncoding.EncodeToString(encryptCBC(t, key, key[:16], plain, wecomBlockSize))
+
+	decrypted, err := decryptWecom(h.key, "corpid", encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, decrypted)
+	_, err = decryptWecom(h.key, "other", encrypted)
+	assert.Error(t, err)
+
+	now := time.Unix(1700000060, 0)
+	signature := sha1Hex("token", "1700000000", "nonce", encrypted)
+	assert.True(t, verifyWecom("token", "1700000000", "nonce", encrypted, signature, now))
+	assert.False(t, verifyWecom("token", "1700000000", "nonce", encrypted, "invalid", now))
+	assert.False(t, verifyWecom("token", "1700000000", "nonce", encrypted, signature, now.Add(time.Hour)))
+}
+
+func TestVerifyLark(t *testing.T) {
+	body := []byte(`{"encrypt":"abc"}`)
+	sum := sha256.Sum256([]byte("1700000000nonce" + "key" + string(body)))
+	signature := hex.EncodeToString(sum[:])
+
+	now := time.Unix(1700000060, 0)
+	assert.True(t, verifyLark("key", "1700000000", "nonce", signature, body, now))
+	assert.False(t, verifyLark("other", "1700000000", "nonce", signature, body, now))
+	assert.False(t, verifyLark("key", "1700000000", "nonce", signature, body, now.Add(time.Hour)))
+}
+
+func TestNewServer(t *testing.T) {
+	_, err := NewServer(Bot{Lark: &Lark{}}, &larkReplier{}, nil)
+	assert.Error(t, err)
+	_, err = NewServer(Bot{Lark: &Lark{VerificationToken: "token"}}, nil, nil)
+	assert.Error(t, err)
+	_, err = NewServer(Bot{Lark: &Lark{VerificationToken: "token"}}, &larkReplier{}, nil)
+	assert.NoError(t, err)
+}
+
+type larkReplier struct{}
+
+func (r *larkReplier) Reply(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func encryptCBC(t *testing.T, key, iv, plain []byte, blockSize int) []byte {
+	block, err := aes.NewCipher(key)
+	assert.NoError(t, err)
+
+	pad := blockSize - len(plain)%blockSize
+	data := append(append([]byte{}, plain...), bytes.Repeat([]byte{byte(pad)}, pad)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+	return data
+}
+
+func sha1Hex(parts ...string) string {
+	sort.Strings(parts)
+	sum := sha1.Sum([]byte(strings.Join(parts, "")))
+	return hex.EncodeToString(sum[:])
+}
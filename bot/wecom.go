@@ -0,0 +1,163 @@
+package bot
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	wecomKeySize     = 32
+	wecomBlockSize   = 32
+	wecomRandomSize  = 16
+	wecomTextMessage = "text"
+)
+
+type (
+	wecomHandler struct {
+		c       *Wecom
+		key     []byte
+		replier Replier
+	}
+
+	wecomEncrypted struct {
+		Encrypt string `xml:"Encrypt"`
+	}
+
+	// https://developer.work.weixin.qq.com/document/path/90239
+	wecomMessage struct {
+		FromUserName string `xml:"FromUserName"`
+		MsgType      string `xml:"MsgType"`
+		Content      string `xml:"Content"`
+	}
+)
+
+func newWecomHandler(c *Wecom, replier Replier) (*wecomHandler, error) {
+	if len(c.CorpId) == 0 {
+		return nil, errors.New("bot: wecom corpId is required")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(c.EncodingAESKey + "=")
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != wecomKeySize {
+		return nil, errors.New("bot: invalid wecom encodingAESKey")
+	}
+
+	return &wecomHandler{
+		c:       c,
+		key:     key,
+		replier: replier,
+	}, nil
+}
+
+// ServeHTTP handles the url verification on GET and the messages on POST,
+// https://developer.work.weixin.qq.com/document/path/90930
+func (h *wecomHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	timestamp := query.Get("timestamp")
+	nonce := query.Get("nonce")
+	signature := query.Get("msg_signature")
+
+	switch r.Method {
+	case http.MethodGet:
+		echo := query.Get("echostr")
+		if !verifyWecom(h.c.Token, timestamp, nonce, echo, signature, time.Now()) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		plain, err := decryptWecom(h.key, h.c.CorpId, echo)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write(plain)
+	case http.MethodPost:
+		body, err := readBody(w, r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var encrypted wecomEncrypted
+		if err := xml.Unmarshal(body, &encrypted); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if !verifyWecom(h.c.Token, timestamp, nonce, encrypted.Encrypt, signature, time.Now()) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		plain, err := decryptWecom(h.key, h.c.CorpId, encrypted.Encrypt)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var msg wecomMessage
+		if err := xml.Unmarshal(plain, &msg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// an empty response means no passive reply, the answer is sent with the app
+		w.WriteHeader(http.StatusOK)
+		if msg.MsgType == wecomTextMessage {
+			reply(h.replier, msg.FromUserName, msg.Content)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func verifyWecom(token, timestamp, nonce, encrypted, signature string, now time.Time) bool {
+	if !fresh(timestamp, now) {
+		return false
+	}
+
+	parts := []string{token, timestamp, nonce, encrypted}
+	sort.Strings(parts)
+	sum := sha1.Sum([]byte(strings.Join(parts, "")))
+	expected := hex.EncodeToString(sum[:])
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) == 1
+}
+
+// decryptWecom returns the message in the plaintext, which is
+// random(16) + length(4) + message + corpid.
+func decryptWecom(key []byte, corpId, encrypted string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := decryptCBC(key, key[:16], data, wecomBlockSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(plain) < wecomRandomSize+4 {
+		return nil, errInvalidCiphertext
+	}
+
+	size := int(binary.BigEndian.Uint32(plain[wecomRandomSize:]))
+	msg := plain[wecomRandomSize+4:]
+	if size > len(msg) {
+		return nil, errInvalidCiphertext
+	}
+	if string(msg[size:]) != corpId {
+		return nil, errors.New("bot: wecom message of another corp")
+	}
+
+	return msg[:size], nil
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"text/template"
+
+	"stargazers/dingtalk"
+	"stargazers/discord"
+	"stargazers/email"
+	"stargazers/gh"
+	"stargazers/gotify"
+	"stargazers/lark"
+	"stargazers/matrix"
+	"stargazers/mattermost"
+	"stargazers/ntfy"
+	"stargazers/rocketchat"
+	"stargazers/sender"
+	"stargazers/slack"
+	"stargazers/teams"
+	"stargazers/telegram"
+	"stargazers/trending"
+	"stargazers/webhook"
+	"stargazers/wecom"
+)
+
+// rateLimits are the messages per minute that the platforms allow for one bot or webhook.
+var rateLimits = map[string]int{
+	"lark":     100,
+	"slack":    60,
+	"wecom":    20,
+	"discord":  30,
+	"telegram": 20,
+	"teams":    60,
+	"dingtalk": 20,
+	"matrix":   60,
+}
+
+type (
+	// SenderConf holds the senders of all the supported platforms.
+	SenderConf struct {
+		Lark       *lark.Lark             `json:"lark,optional"`
+		Slack      *slack.Slack           `json:"slack,optional"`
+		Wecom      *wecom.Wecom           `json:"wecom,optional"`
+		Discord    *discord.Discord       `json:"discord,optional"`
+		Telegram   *telegram.Telegram     `json:"telegram,optional"`
+		Teams      *teams.Teams           `json:"teams,optional"`
+		DingTalk   *dingtalk.DingTalk     `json:"dingtalk,optional"`
+		Email      *email.Email           `json:"email,optional"`
+		Webhook    *webhook.Webhook       `json:"webhook,optional"`
+		Matrix     *matrix.Matrix         `json:"matrix,optional"`
+		Mattermost *mattermost.Mattermost `json:"mattermost,optional"`
+		RocketChat *rocketchat.RocketChat `json:"rocketchat,optional"`
+		Ntfy       *ntfy.Ntfy             `json:"ntfy,optional"`
+		Gotify     *gotify.Gotify         `json:"gotify,optional"`
+		// Resilience applies to all the senders, a channel falls back to the global one if not set.
+		Resilience *sender.ResilientConf `json:"resilience,optional"`
+	}
+
+	ChannelConf struct {
+		Name string `json:"name"`
+		SenderConf
+		// Templates override the global templates for this channel.
+		Templates map[string]string `json:"templates,optional"`
+	}
+)
+
+func eventTypes() []string {
+	events := []string{trending.TrendingEvent, sender.ErrorEvent}
+	for _, event := range gh.EventTypes {
+		events = append(events, string(event))
+	}
+
+	return events
+}
+
+func getChannels(c Config) ([]sender.Channel, error) {
+	confs := append([]ChannelConf{{
+		SenderConf: c.SenderConf,
+	}}, c.Channels...)
+
+	var channels []sender.Channel
+	names := make(map[string]struct{})
+	for _, cc := range confs {
+		templates, err := parseTemplates(c.Templates, cc.Templates)
+		if err != nil {
+			return nil, fmt.Errorf("channel %q: %w", cc.Name, err)
+		}
+
+		if cc.Resilience == nil {
+			cc.Resilience = c.Resilience
+		}
+		chs, err := newChannels(cc.Name, cc)
+		if err != nil {
+			return nil, fmt.Errorf("channel %q: %w", cc.Name, err)
+		}
+
+		for _, ch := range chs {
+			// the routes and the logs tell the channels apart by names
+			if _, ok := names[ch.Name]; ok {
+				return nil, fmt.Errorf("duplicate channel name %q", ch.Name)
+			}
+			names[ch.Name] = struct{}{}
+
+			ch.Sender = sender.NewTemplateSender(ch.Sender, templates)
+			channels = append(channels, ch)
+		}
+	}
+
+	return channels, nil
+}
+
+func getSender(c Config) (*sender.MultiSender, error) {
+	channels, err := getChannels(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(channels) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(channels))
+	for _, ch := range channels {
+		names = append(names, ch.Name)
+	}
+	routes, err := sender.ParseRoutes(c.Routes, eventTypes(), names)
+	if err != nil {
+		return nil, err
+	}
+
+	return sender.NewMultiSender(routes, channels...), nil
+}
+
+// newChannels names the channels after the platforms, prefixed with name if given.
+// A named entry with only one platform is named as is.
+// Each sender is wrapped with the resilience and the rate limit of its platform.
+func newChannels(name string, c ChannelConf) ([]sender.Channel, error) {
+	var channels []sender.Channel
+	if c.Lark != nil {
+		channels = append(channels, sender.Channel{Name: "lark", Sender: lark.NewSender(c.Lark)})
+	}
+	if c.Slack != nil {
+		channels = append(channels, sender.Channel{Name: "slack", Sender: slack.NewSender(c.Slack)})
+	}
+	if c.Wecom != nil {
+		channels = append(channels, sender.Channel{Name: "wecom", Sender: wecom.NewSender(c.Wecom)})
+	}
+	if c.Discord != nil {
+		channels = append(channels, sender.Channel{Name: "discord", Sender: discord.NewSender(c.Discord)})
+	}
+	if c.Telegram != nil {
+		channels = append(channels, sender.Channel{Name: "telegram", Sender: telegram.NewSender(c.Telegram)})
+	}
+	if c.Teams != nil {
+		channels = append(channels, sender.Channel{Name: "teams", Sender: teams.NewSender(c.Teams)})
+	}
+	if c.DingTalk != nil {
+		channels = append(channels, sender.Channel{Name: "dingtalk", Sender: dingtalk.NewSender(c.DingTalk)})
+	}
+	if c.Email != nil {
+		s, err := email.NewSender(c.Email)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, sender.Channel{Name: "email", Sender: s})
+	}
+	if c.Webhook != nil {
+		s, err := webhook.NewSender(c.Webhook)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, sender.Channel{Name: "webhook", Sender: s})
+	}
+	if c.Matrix != nil {
+		channels = append(channels, sender.Channel{Name: "matrix", Sender: matrix.NewSender(c.Matrix)})
+	}
+	if c.Mattermost != nil {
+		channels = append(channels, sender.Channel{Name: "mattermost", Sender: mattermost.NewSender(c.Mattermost)})
+	}
+	if c.RocketChat != nil {
+		channels = append(channels, sender.Channel{Name: "rocketchat", Sender: rocketchat.NewSender(c.RocketChat)})
+	}
+	if c.Ntfy != nil {
+		channels = append(channels, sender.Channel{Name: "ntfy", Sender: ntfy.NewSender(c.Ntfy)})
+	}
+	if c.Gotify != nil {
+		channels = append(channels, sender.Channel{Name: "gotify", Sender: gotify.NewSender(c.Gotify)})
+	}
+
+	for i, ch := range channels {
+		platform := ch.Name
+		switch {
+		case len(name) == 0:
+		case len(channels) == 1:
+			ch.Name = name
+		default:
+			ch.Name = name + "/" + platform
+		}
+
+		rc := sender.DefaultResilientConf
+		if c.Resilience != nil {
+			rc = *c.Resilience
+		}
+		if rc.RateLimit == 0 {
+			rc.RateLimit = rateLimits[platform]
+		}
+		ch.Sender = sender.NewResilientSender(ch.Name, ch.Sender, rc)
+		channels[i] = ch
+	}
+
+	return channels, nil
+}
+
+func parseTemplates(global, local map[string]string) (map[string]*template.Template, error) {
+	texts := maps.Clone(global)
+	if texts == nil {
+		texts = make(map[string]string)
+	}
+	maps.Copy(texts, local)
+
+	return sender.ParseTemplates(texts, eventTypes())
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"stargazers/discord"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChannelsDuplicate(t *testing.T) {
+	hook := &discord.Discord{WebhookUrl: "https://discord.com/api/webhooks/1/token"}
+	c := Config{
+		SenderConf: SenderConf{Discord: hook},
+		Channels: []ChannelConf{
+			{Name: "community", SenderConf: SenderConf{Discord: hook}},
+		},
+	}
+	channels, err := getChannels(c)
+	assert.NoError(t, err)
+	assert.Len(t, channels, 2)
+
+	c.Channels = append(c.Channels, ChannelConf{Name: "community", SenderConf: SenderConf{Discord: hook}})
+	_, err = getChannels(c)
+	assert.EqualError(t, err, `duplicate channel name "community"`)
+
+	c.Channels = []ChannelConf{{Name: "discord", SenderConf: SenderConf{Discord: hook}}}
+	_, err = getChannels(c)
+	assert.EqualError(t, err, `duplicate channel name "discord"`)
+}
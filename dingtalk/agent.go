@@ -0,0 +1,207 @@
+package dingtalk
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/zeromicro/go-zero/rest/httpc"
+)
+
+const (
+	textMessageType     = "text"
+	markdownMessageType = "markdown"
+	// over 20 messages per minute, the robot is throttled for 10 minutes,
+	// the rate is kept by the limiter of the resilient sender
+	throttledWait = time.Minute * 10
+	// the content of the custom robots is limited to 20000 bytes
+	maxTextSize = 20000
+)
+
+// https://open.dingtalk.com/document/orgapp/custom-robot-access#title-nwb-pck-4i1
+var errorMessages = map[int]string{
+	130101: "sending too fast, over 20 messages per minute",
+	300001: "invalid access token",
+	310000: "security check failed, check the keyword, the secret or the ip whitelist",
+	400013: "the group was disbanded",
+	410100: "sending too fast, rate limited",
+}
+
+type (
+	app struct {
+		c     *DingTalk
+		parts sender.Parts
+	}
+
+	textBody struct {
+		Content string `json:"content"`
+	}
+
+	markdownBody struct {
+		Title string `json:"title"`
+		Text  string `json:"text"`
+	}
+
+	textRequest struct {
+		MsgType string   `json:"msgtype"`
+		Text    textBody `json:"text"`
+	}
+
+	markdownRequest struct {
+		MsgType  string       `json:"msgtype"`
+		Markdown markdownBody `json:"markdown"`
+	}
+
+	response struct {
+		Code int    `json:"errcode"`
+		Msg  string `json:"errmsg"`
+	}
+)
+
+func NewSender(c *DingTalk) sender.Sender {
+	return &app{c: c}
+}
+
+func (a *app) Send(message string) error {
+	return a.SendContext(context.Background(), message)
+}
+
+func (a *app) SendContext(ctx context.Context, message string) error {
+	parts := sender.Split(message, a.textLimit(), sender.Bytes)
+	return a.parts.Send(message, parts, func(part string) error {
+		return a.post(ctx, textRequest{
+			MsgType: textMessageType,
+			Text: textBody{
+				Content: a.withKeyword(part),
+			},
+		})
+	})
+}
+
+func (a *app) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *app) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	title := msg.Title
+	if len(title) == 0 {
+		title = "stargazers"
+	}
+
+	return a.post(ctx, markdownRequest{
+		MsgType: markdownMessageType,
+		Markdown: markdownBody{
+			Title: title,
+			Text:  a.withKeyword(sender.Truncate(renderMarkdown(msg), a.textLimit(), sender.Bytes)),
+		},
+	})
+}
+
+func (a *app) post(ctx context.Context, req any) error {
+	target, err := a.signedUrl(time.Now())
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpc.Do(ctx, http.MethodPost, target, req)
+	if err != nil {
+		return err
+	}
+
+	var rsp response
+	if err := httpc.Parse(resp, &rsp); err != nil {
+		return err
+	}
+
+	if rsp.Code == 0 {
+		return nil
+	}
+
+	err = fmt.Errorf("dingtalk: %d %s", rsp.Code, rsp.Msg)
+	if desc, ok := errorMessages[rsp.Code]; ok {
+		err = fmt.Errorf("dingtalk: %d %s, %s", rsp.Code, desc, rsp.Msg)
+	}
+	switch rsp.Code {
+	case 130101, 410100:
+		return &sender.RetryAfterError{
+			Err:   err,
+			After: throttledWait,
+		}
+	case 300001, 310000, 400013:
+		// the token, the security settings or the group need fixing, retrying won't help
+		return sender.Permanent(err)
+	default:
+		return err
+	}
+}
+
+// signedUrl adds the timestamp and the signature to the webhook url if the secret is set.
+func (a *app) signedUrl(now time.Time) (string, error) {
+	if len(a.c.Secret) == 0 {
+		return a.c.WebhookUrl, nil
+	}
+
+	u, err := url.Parse(a.c.WebhookUrl)
+	if err != nil {
+		return "", err
+	}
+
+	timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+	query := u.Query()
+	query.Set("timestamp", timestamp)
+	query.Set("sign", sign(timestamp, a.c.Secret))
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// textLimit leaves the room for the keyword.
+func (a *app) textLimit() int {
+	if len(a.c.Keyword) == 0 {
+		return maxTextSize
+	}
+
+	return maxTextSize - len(a.c.Keyword) - 1
+}
+
+func (a *app) withKeyword(text string) string {
+	if len(a.c.Keyword) == 0 || strings.Contains(text, a.c.Keyword) {
+		return text
+	}
+
+	return text + "\n" + a.c.Keyword
+}
+
+func renderMarkdown(msg sender.Message) string {
+	var lines []string
+	if len(msg.Title) > 0 {
+		lines = append(lines, "#### "+msg.Title)
+	}
+	for _, field := range msg.Fields {
+		lines = append(lines, fmt.Sprintf("- **%s**: %s", field.Name, field.Value))
+	}
+	if len(msg.Text) > 0 {
+		lines = append(lines, msg.Text)
+	}
+	for _, link := range msg.Links {
+		lines = append(lines, fmt.Sprintf("[%s](%s)", link.Title, link.URL))
+	}
+
+	// markdown of DingTalk needs an empty line to break lines
+	return strings.Join(lines, "\n\n")
+}
+
+func sign(timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,57 @@
+package dingtalk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignedUrl(t *testing.T) {
+	a := NewSender(&DingTalk{
+		WebhookUrl: "https://oapi.dingtalk.com/robot/send?access_token=abc",
+		Secret:     "SECabc",
+	}).(*app)
+
+	target, err := a.signedUrl(time.UnixMilli(1700000000000))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://oapi.dingtalk.com/robot/send?access_token=abc"+
+		"&sign=jcUpW0QmtKduN03n4JqQ0PBosVjqnM8gU7fIIvsDmCM%3D&timestamp=1700000000000", target)
+}
+
+func TestWithKeyword(t *testing.T) {
+	a := NewSender(&DingTalk{
+		Keyword: "stars",
+	}).(*app)
+
+	assert.Equal(t, "stars: 100", a.withKeyword("stars: 100"))
+	assert.Equal(t, "unstar\nstars", a.withKeyword("unstar"))
+}
+
+func TestSendThrottled(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"errcode": 410100, "errmsg": "send too fast"}`))
+	}))
+	defer svr.Close()
+
+	var rae *sender.RetryAfterError
+	if assert.ErrorAs(t, NewSender(&DingTalk{WebhookUrl: svr.URL}).Send("hello"), &rae) {
+		assert.Equal(t, throttledWait, rae.After)
+	}
+}
+
+func TestSendRejected(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"errcode": 310000, "errmsg": "keywords not in content"}`))
+	}))
+	defer svr.Close()
+
+	var pe *sender.PermanentError
+	assert.ErrorAs(t, NewSender(&DingTalk{WebhookUrl: svr.URL}).Send("hello"), &pe)
+}
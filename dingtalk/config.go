@@ -0,0 +1,10 @@
+package dingtalk
+
+type DingTalk struct {
+	// WebhookUrl is like https://oapi.dingtalk.com/robot/send?access_token=<token>
+	WebhookUrl string `json:"webhookUrl"`
+	// Secret is the secret of the signature security mode, starting with SEC.
+	Secret string `json:"secret,optional"`
+	// Keyword is added to the messages without it, if the keyword security mode is on.
+	Keyword string `json:"keyword,optional"`
+}
@@ -0,0 +1,207 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/zeromicro/go-zero/rest/httpc"
+)
+
+const (
+	// https://discord.com/developers/docs/resources/message#embed-object-embed-limits
+	maxContentLength   = 2000
+	maxTitleLength     = 256
+	maxFields          = 25
+	maxFieldLength     = 1024
+	maxDescriptionText = 3000
+	maxEmbedLength     = 6000
+	// emptyValue takes the place of the empty field names and values, which Discord rejects
+	emptyValue = "-"
+)
+
+type (
+	app struct {
+		c     *Discord
+		parts sender.Parts
+	}
+
+	request struct {
+		Content   string  `json:"content,omitempty"`
+		Username  string  `json:"username,omitempty"`
+		AvatarUrl string  `json:"avatar_url,omitempty"`
+		Embeds    []embed `json:"embeds,omitempty"`
+	}
+
+	embed struct {
+		Title       string     `json:"title,omitempty"`
+		Description string     `json:"description,omitempty"`
+		Url         string     `json:"url,omitempty"`
+		Color       int        `json:"color,omitempty"`
+		Fields      []field    `json:"fields,omitempty"`
+		Thumbnail   *thumbnail `json:"thumbnail,omitempty"`
+	}
+
+	field struct {
+		Name   string `json:"name"`
+		Value  string `json:"value"`
+		Inline bool   `json:"inline"`
+	}
+
+	thumbnail struct {
+		Url string `json:"url"`
+	}
+
+	// https://discord.com/developers/docs/topics/rate-limits#exceeding-a-rate-limit
+	rateLimitResponse struct {
+		Message    string  `json:"message"`
+		RetryAfter float64 `json:"retry_after"`
+	}
+)
+
+func NewSender(c *Discord) sender.Sender {
+	return &app{c: c}
+}
+
+func (a *app) Send(message string) error {
+	return a.SendContext(context.Background(), message)
+}
+
+func (a *app) SendContext(ctx context.Context, message string) error {
+	parts := sender.Split(message, maxContentLength, sender.Runes)
+	return a.parts.Send(message, parts, func(part string) error {
+		return a.post(ctx, request{
+			Content:   part,
+			Username:  a.c.Username,
+			AvatarUrl: a.c.AvatarUrl,
+		})
+	})
+}
+
+func (a *app) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *app) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	return a.post(ctx, request{
+		Username:  a.c.Username,
+		AvatarUrl: a.c.AvatarUrl,
+		Embeds:    []embed{renderEmbed(msg)},
+	})
+}
+
+// post sends the request, the time to wait is returned if rate limited.
+func (a *app) post(ctx context.Context, req request) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, a.c.WebhookUrl, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpc.DoRequest(r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		var rsp rateLimitResponse
+		if err := json.Unmarshal(body, &rsp); err != nil {
+			return &sender.RetryAfterError{
+				Err:   fmt.Errorf("discord: %s", resp.Status),
+				After: sender.ParseRetryAfter(resp.Header.Get("Retry-After")),
+			}
+		}
+
+		return &sender.RetryAfterError{
+			Err:   fmt.Errorf("discord: %s", rsp.Message),
+			After: time.Duration(rsp.RetryAfter * float64(time.Second)),
+		}
+	case resp.StatusCode >= http.StatusBadRequest:
+		return sender.StatusError(resp.StatusCode,
+			fmt.Errorf("discord: %s, %s", resp.Status, strings.TrimSpace(string(body))))
+	default:
+		return nil
+	}
+}
+
+// renderEmbed keeps the embed within 6000 characters in total, the fields take the room
+// left by the title and the links, then the text takes the rest.
+func renderEmbed(msg sender.Message) embed {
+	e := embed{
+		Title: sender.Truncate(msg.Title, maxTitleLength, sender.Runes),
+		Color: colorValue(msg.Color),
+	}
+	if len(msg.Avatar) > 0 {
+		e.Thumbnail = &thumbnail{
+			Url: msg.Avatar,
+		}
+	}
+
+	var links []string
+	for _, link := range msg.Links {
+		links = append(links, fmt.Sprintf("[%s](%s)", link.Title, link.URL))
+	}
+	if len(msg.Links) > 0 {
+		e.Url = msg.Links[0].URL
+	}
+
+	used := sender.Runes(e.Title) + sender.Runes(strings.Join(links, "\n"))
+	for _, f := range msg.Fields[:min(len(msg.Fields), maxFields)] {
+		fd := field{
+			Name:   sender.Truncate(orEmpty(f.Name), maxTitleLength, sender.Runes),
+			Value:  sender.Truncate(orEmpty(f.Value), maxFieldLength, sender.Runes),
+			Inline: true,
+		}
+		size := sender.Runes(fd.Name) + sender.Runes(fd.Value)
+		if used+size > maxEmbedLength {
+			break
+		}
+		e.Fields = append(e.Fields, fd)
+		used += size
+	}
+
+	// the description takes the text and the links, 4096 characters at most
+	if room := min(maxDescriptionText, maxEmbedLength-used-1); len(msg.Text) > 0 && room > 1 {
+		links = append([]string{sender.Truncate(msg.Text, room, sender.Runes)}, links...)
+	}
+	e.Description = strings.Join(links, "\n")
+
+	return e
+}
+
+func orEmpty(s string) string {
+	if len(strings.TrimSpace(s)) == 0 {
+		return emptyValue
+	}
+
+	return s
+}
+
+func colorValue(color sender.Color) int {
+	val, err := strconv.ParseInt(strings.TrimPrefix(color.Hex(), "#"), 16, 32)
+	if err != nil {
+		return 0
+	}
+
+	return int(val)
+}
@@ -0,0 +1,71 @@
+package discord
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderEmbedTotal(t *testing.T) {
+	msg := sender.Message{
+		Title: strings.Repeat("t", 300),
+		Text:  strings.Repeat("text\n", 1000),
+	}
+	for i := 0; i < 30; i++ {
+		msg.AddField(strings.Repeat("n", 300), strings.Repeat("v", 2000))
+	}
+	msg.AddLink("profile", "https://github.com/kevwan")
+
+	e := renderEmbed(msg)
+	total := sender.Runes(e.Title) + sender.Runes(e.Description)
+	for _, f := range e.Fields {
+		total += sender.Runes(f.Name) + sender.Runes(f.Value)
+	}
+	assert.LessOrEqual(t, total, maxEmbedLength)
+	assert.Len(t, e.Fields, 4)
+	assert.True(t, strings.HasSuffix(e.Description, "[profile](https://github.com/kevwan)"))
+}
+
+func TestRenderEmbedEmptyField(t *testing.T) {
+	var msg sender.Message
+	msg.AddField("name", "")
+	assert.Equal(t, []field{{Name: "name", Value: emptyValue, Inline: true}}, renderEmbed(msg).Fields)
+}
+
+func TestSendRateLimited(t *testing.T) {
+	var calls int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message": "You are being rate limited.", "retry_after": 0.25, "global": false}`))
+			return
+		}
+
+		var req request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "hello", req.Content)
+		assert.Equal(t, "stargazers", req.Username)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer svr.Close()
+
+	s := NewSender(&Discord{
+		WebhookUrl: svr.URL,
+		Username:   "stargazers",
+	})
+	var rae *sender.RetryAfterError
+	if assert.ErrorAs(t, s.Send("hello"), &rae) {
+		assert.Equal(t, time.Millisecond*250, rae.After)
+	}
+	assert.NoError(t, s.Send("hello"))
+	assert.Equal(t, 2, calls)
+}
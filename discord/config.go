@@ -0,0 +1,7 @@
+package discord
+
+type Discord struct {
+	WebhookUrl string `json:"webhookUrl"`
+	Username   string `json:"username,optional"`
+	AvatarUrl  string `json:"avatarUrl,optional"`
+}
@@ -0,0 +1,133 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"stargazers/sender"
+)
+
+const (
+	dialTimeout  = time.Second * 10
+	tlsImplicit  = "implicit"
+	tlsStartTLS  = "starttls"
+	tlsNone      = "none"
+	defaultTitle = "notification"
+)
+
+type app struct {
+	c *Email
+}
+
+// NewSender returns an email sender. smtp.PlainAuth refuses to send the password
+// without TLS to a host other than localhost, so the config is rejected here instead.
+func NewSender(c *Email) (sender.Sender, error) {
+	if c.TLS == tlsNone && len(c.Username) > 0 && !isLocalhost(c.Host) {
+		return nil, fmt.Errorf("email: tls none can't authenticate to %s, only to localhost", c.Host)
+	}
+
+	return &app{c: c}, nil
+}
+
+func (a *app) Send(message string) error {
+	return a.SendContext(context.Background(), message)
+}
+
+func (a *app) SendContext(ctx context.Context, message string) error {
+	return a.SendMessageContext(ctx, sender.Message{
+		Text: message,
+	})
+}
+
+func (a *app) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *app) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	title := msg.Title
+	if len(title) == 0 {
+		title = defaultTitle
+	}
+
+	body, err := buildMessage(a.c.From, a.c.To, a.c.Subject+": "+title, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return a.deliver(ctx, body)
+}
+
+func (a *app) deliver(ctx context.Context, body []byte) error {
+	addr := net.JoinHostPort(a.c.Host, strconv.Itoa(a.c.Port))
+	dialer := net.Dialer{
+		Timeout: dialTimeout,
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	// net/smtp has no context, close the connection to interrupt it once ctx is done
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	tlsConfig := &tls.Config{
+		ServerName: a.c.Host,
+	}
+	if a.c.TLS == tlsImplicit {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	cli, err := smtp.NewClient(conn, a.c.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer cli.Close()
+
+	if a.c.TLS == tlsStartTLS {
+		if err := cli.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	if len(a.c.Username) > 0 {
+		if err := cli.Auth(smtp.PlainAuth("", a.c.Username, a.c.Password, a.c.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := cli.Mail(a.c.From); err != nil {
+		return err
+	}
+	for _, to := range a.c.To {
+		if err := cli.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := cli.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return cli.Quit()
+}
+
+// isLocalhost tells whether smtp.PlainAuth takes host as localhost.
+func isLocalhost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
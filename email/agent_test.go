@@ -0,0 +1,21 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSender(t *testing.T) {
+	_, err := NewSender(&Email{Host: "smtp.example.com", TLS: tlsNone, Username: "user"})
+	assert.Error(t, err)
+
+	_, err = NewSender(&Email{Host: "localhost", TLS: tlsNone, Username: "user"})
+	assert.NoError(t, err)
+
+	_, err = NewSender(&Email{Host: "smtp.example.com", TLS: tlsNone})
+	assert.NoError(t, err)
+
+	_, err = NewSender(&Email{Host: "smtp.example.com", TLS: tlsStartTLS, Username: "user"})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,13 @@
+package email
+
+type Email struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port,default=587"`
+	Username string   `json:"username,optional"`
+	Password string   `json:"password,optional"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	// TLS is starttls for port 587, implicit for port 465, or none for local relays.
+	TLS     string `json:"tls,default=starttls,options=starttls|implicit|none"`
+	Subject string `json:"subject,default=stargazers"`
+}
@@ -0,0 +1,94 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"stargazers/sender"
+)
+
+var htmlTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: -apple-system, Helvetica, Arial, sans-serif; font-size: 14px;">
+{{- if .Title}}
+<h3 style="border-left: 4px solid {{.Color}}; padding-left: 8px;">{{.Title}}</h3>
+{{- end}}
+{{- if .Avatar}}
+<img src="{{.Avatar}}" alt="avatar" width="64" height="64" style="border-radius: 50%;">
+{{- end}}
+{{- if .Fields}}
+<table cellpadding="4">
+{{- range .Fields}}
+<tr><td style="color: #666;">{{.Name}}</td><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Text}}
+<pre style="font-family: inherit;">{{.Text}}</pre>
+{{- end}}
+{{- range .Links}}
+<p><a href="{{.URL}}">{{.Title}}</a></p>
+{{- end}}
+</body>
+</html>
+`))
+
+// buildMessage builds a multipart/alternative message with the plain text and the html bodies.
+func buildMessage(from string, to []string, subject string, msg sender.Message, now time.Time) ([]byte, error) {
+	var html bytes.Buffer
+	if err := htmlTemplate.Execute(&html, struct {
+		sender.Message
+		Color string
+	}{
+		Message: msg,
+		Color:   msg.Color.Hex(),
+	}); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprint(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{contentType: "text/plain; charset=utf-8", content: msg.String()},
+		{contentType: "text/html; charset=utf-8", content: html.String()},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
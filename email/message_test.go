@@ -0,0 +1,55 @@
+package email
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildMessage(t *testing.T) {
+	msg := sender.Message{
+		Title: "new star",
+		Color: sender.ColorGreen,
+	}
+	msg.AddField("user", "<kevwan>")
+	msg.AddLink("profile", "https://github.com/kevwan")
+
+	body, err := buildMessage("bot@example.com", []string{"a@example.com", "b@example.com"},
+		"stargazers: new star", msg, time.Unix(0, 0))
+	assert.NoError(t, err)
+
+	m, err := mail.ReadMessage(strings.NewReader(string(body)))
+	assert.NoError(t, err)
+	assert.Equal(t, "a@example.com, b@example.com", m.Header.Get("To"))
+	assert.Equal(t, "stargazers: new star", m.Header.Get("Subject"))
+
+	mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	var parts []string
+	mr := multipart.NewReader(m.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		content, err := io.ReadAll(part)
+		assert.NoError(t, err)
+		parts = append(parts, string(content))
+	}
+
+	assert.Len(t, parts, 2)
+	assert.Equal(t, strings.ReplaceAll(msg.String(), "\n", "\r\n"), parts[0])
+	assert.Contains(t, parts[1], "&lt;kevwan&gt;")
+	assert.Contains(t, parts[1], `<a href="https://github.com/kevwan">profile</a>`)
+}
@@ -4,12 +4,45 @@ import "time"
 
 type Config struct {
 	Token       string        `json:"token"`
-	Repo        string        `json:"repo"`
+	Repo        string        `json:"repo,optional"`
+	Org         *Org          `json:"org,optional"`
 	Comparisons []string      `json:"comparisons,optional"`
 	Interval    time.Duration `json:"interval,default=1m"`
 	Expect      *struct {
 		Date  string `json:"date"`
 		Stars int    `json:"stars"`
 	} `json:"expect,optional"`
-	Verbose bool `json:"verbose,default=false"`
+	// Milestone reports every time the stars reach a multiple of it, like 1000, disabled if 0.
+	Milestone int `json:"milestone,optional"`
+	// Watchlist holds the logins of notable users or organizations,
+	// an organization matches its public members and the users working there.
+	Watchlist []string  `json:"watchlist,optional"`
+	Traffic   *Traffic  `json:"traffic,optional"`
+	Releases  *Releases `json:"releases,optional"`
+	// Deprecated: the unstar events are always reported, kept for the existing configs.
+	Verbose bool `json:"verbose,optional"`
+}
+
+type Org struct {
+	// Name is the organization or user whose public repos are monitored.
+	Name     string        `json:"name"`
+	Include  []string      `json:"include,optional"`
+	Exclude  []string      `json:"exclude,optional"`
+	MinStars int           `json:"minStars,default=0"`
+	Discover time.Duration `json:"discover,default=1h"`
+}
+
+type Traffic struct {
+	// Path is the file to keep the traffic history.
+	Path         string        `json:"path,default=traffic.json"`
+	Interval     time.Duration `json:"interval,default=1h"`
+	TopReferrers int           `json:"topReferrers,default=5"`
+	// ReferrerAlert is the views of a new referrer to trigger an alert.
+	ReferrerAlert int `json:"referrerAlert,default=50"`
+}
+
+type Releases struct {
+	// Window is the duration before and after a release to count the stars.
+	Window   time.Duration `json:"window,default=72h"`
+	Interval time.Duration `json:"interval,default=1h"`
 }
@@ -0,0 +1,95 @@
+package gh
+
+import (
+	"sync"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// errorReportInterval keeps an outage of GitHub from flooding the channels.
+const errorReportInterval = time.Hour
+
+type (
+	EventType string
+
+	Event struct {
+		Type EventType
+		// Repo is the full name of the repo that the event is about.
+		Repo    string
+		Message sender.Message
+	}
+)
+
+const (
+	StarEvent      EventType = sender.StarEvent
+	UnstarEvent    EventType = sender.UnstarEvent
+	DeletedEvent   EventType = sender.DeletedEvent
+	SuspendedEvent EventType = sender.SuspendedEvent
+	RenamedEvent   EventType = sender.RenamedEvent
+	NewRepoEvent   EventType = sender.NewRepoEvent
+	VIPEvent       EventType = sender.VIPEvent
+	TrafficEvent   EventType = sender.TrafficEvent
+	ReferrerEvent  EventType = sender.ReferrerEvent
+	ReleaseEvent   EventType = sender.ReleaseEvent
+	MilestoneEvent EventType = sender.MilestoneEvent
+	// ErrorEvent is shared with the other monitors, so it's not in EventTypes.
+	ErrorEvent EventType = sender.ErrorEvent
+)
+
+// EventTypes are all the event types that gh emits.
+var EventTypes = []EventType{
+	StarEvent,
+	UnstarEvent,
+	DeletedEvent,
+	SuspendedEvent,
+	RenamedEvent,
+	NewRepoEvent,
+	VIPEvent,
+	TrafficEvent,
+	ReferrerEvent,
+	ReleaseEvent,
+	MilestoneEvent,
+}
+
+// errorReports holds the last time that each failure was reported.
+var errorReports = struct {
+	sync.Mutex
+	at map[string]time.Time
+}{
+	at: make(map[string]time.Time),
+}
+
+func (e Event) message() sender.Message {
+	msg := e.Message
+	msg.Event = string(e.Type)
+	msg.Repo = e.Repo
+	return msg
+}
+
+// reportError logs err, and queues an error event of what failed on repo,
+// the same failure is queued once an hour at most.
+func reportError(repo, what string, err error) {
+	logx.Errorf("%s %s - %v", repo, what, err)
+
+	key := repo + " " + what
+	errorReports.Lock()
+	if last, ok := errorReports.at[key]; ok && time.Since(last) < errorReportInterval {
+		errorReports.Unlock()
+		return
+	}
+	errorReports.at[key] = time.Now()
+	errorReports.Unlock()
+
+	fifo.Put(Event{
+		Type: ErrorEvent,
+		Repo: repo,
+		Message: sender.Message{
+			Title: what,
+			Text:  err.Error(),
+			Color: sender.ColorRed,
+		},
+	})
+}
@@ -0,0 +1,23 @@
+package gh
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportError(t *testing.T) {
+	takeEvents()
+	reportError("zeromicro/go-zero", "refresh", errors.New("502 Bad Gateway"))
+	reportError("zeromicro/go-zero", "refresh", errors.New("502 Bad Gateway"))
+	reportError("zeromicro/go-zero", "traffic", errors.New("403 Forbidden"))
+
+	events := takeEvents()
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, ErrorEvent, events[0].Type)
+		assert.Equal(t, "zeromicro/go-zero", events[0].Repo)
+		assert.Equal(t, "refresh\n502 Bad Gateway", events[0].Message.String())
+		assert.Equal(t, "traffic\n403 Forbidden", events[1].Message.String())
+	}
+}
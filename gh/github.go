@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -32,8 +33,29 @@ func ParseRepo(repo string) (owner, project string, err error) {
 	return
 }
 
+type Stargazer struct {
+	Login     string
+	StarredAt time.Time
+}
+
 func RequestAll(cli *github.Client, owner, project string) (map[string]time.Time, error) {
-	stars := make(map[string]time.Time)
+	gazers, err := RequestStargazers(cli, owner, project)
+	if err != nil {
+		return nil, err
+	}
+
+	stars := make(map[string]time.Time, len(gazers))
+	for _, gazer := range gazers {
+		stars[gazer.Login] = gazer.StarredAt
+	}
+
+	return stars, nil
+}
+
+// RequestStargazers returns all the stargazers keyed by their numeric user ID,
+// which stays the same when users rename their accounts.
+func RequestStargazers(cli *github.Client, owner, project string) (map[int64]Stargazer, error) {
+	stars := make(map[int64]Stargazer)
 	var page = 1
 	for {
 		logx.Infof("requesting page %d", page)
@@ -47,9 +69,12 @@ func RequestAll(cli *github.Client, owner, project string) (map[string]time.Time
 		}
 
 		for _, gazer := range gazers {
-			id := *gazer.User.Login
+			id := gazer.User.GetID()
 			if _, ok := stars[id]; !ok {
-				stars[id] = gazer.StarredAt.Time
+				stars[id] = Stargazer{
+					Login:     gazer.User.GetLogin(),
+					StarredAt: gazer.StarredAt.Time,
+				}
 			}
 		}
 
@@ -70,3 +95,85 @@ func RequestUser(cli *github.Client, id string) (*github.User, error) {
 
 	return user, nil
 }
+
+const latestStargazersQuery = `query($owner: String!, $name: String!, $count: Int!) {
+  repository(owner: $owner, name: $name) {
+    stargazers(first: $count, orderBy: {field: STARRED_AT, direction: DESC}) {
+      edges {
+        starredAt
+        node {
+          login
+          databaseId
+        }
+      }
+    }
+  }
+}`
+
+type (
+	graphqlRequest struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+
+	latestStargazersResponse struct {
+		Data struct {
+			Repository *struct {
+				Stargazers struct {
+					Edges []struct {
+						StarredAt time.Time `json:"starredAt"`
+						Node      struct {
+							Login      string `json:"login"`
+							DatabaseId int64  `json:"databaseId"`
+						} `json:"node"`
+					} `json:"edges"`
+				} `json:"stargazers"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+)
+
+// RequestLatestStargazers returns the latest count stargazers, the latest first.
+// It uses GraphQL, because the REST API only serves the first 400 pages of the stargazers,
+// which are the oldest ones of the popular repos. GraphQL needs a token.
+func RequestLatestStargazers(cli *github.Client, owner, project string, count int) ([]*github.Stargazer, error) {
+	req, err := cli.NewRequest(http.MethodPost, "graphql", graphqlRequest{
+		Query: latestStargazersQuery,
+		Variables: map[string]any{
+			"owner": owner,
+			"name":  project,
+			"count": count,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rsp latestStargazersResponse
+	if _, err := cli.Do(context.Background(), req, &rsp); err != nil {
+		return nil, fmt.Errorf("failed to fetch stargazers of %s/%s, error: %v", owner, project, err)
+	}
+	if len(rsp.Errors) > 0 {
+		return nil, fmt.Errorf("failed to fetch stargazers of %s/%s, error: %s",
+			owner, project, rsp.Errors[0].Message)
+	}
+	if rsp.Data.Repository == nil {
+		return nil, fmt.Errorf("repo %s/%s not found", owner, project)
+	}
+
+	var gazers []*github.Stargazer
+	for _, edge := range rsp.Data.Repository.Stargazers.Edges {
+		gazers = append(gazers, &github.Stargazer{
+			StarredAt: &github.Timestamp{Time: edge.StarredAt},
+			User: &github.User{
+				ID:    github.Int64(edge.Node.DatabaseId),
+				Login: github.String(edge.Node.Login),
+			},
+		})
+	}
+
+	return gazers, nil
+}
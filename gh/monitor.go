@@ -2,9 +2,10 @@ package gh
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"strings"
+	"sync"
 	"time"
 
 	"stargazers/sender"
@@ -24,33 +25,71 @@ const (
 )
 
 var (
-	stargazers = make(map[string]time.Time)
-	dayStars   = make(map[string]int)
-	startTime  = time.Now()
-	fifo       = collection.NewQueue(queueSize)
+	startTime = time.Now()
+	fifo      = collection.NewQueue(queueSize)
 )
 
-type Monitor struct {
-	cfg    Config
-	cli    *github.Client
-	sender sender.Sender
-}
+type (
+	Monitor struct {
+		cfg    Config
+		cli    *github.Client
+		sender sender.Sender
+		state  *repoState
+		// orgStars returns the aggregate stars of the org in org mode, nil otherwise.
+		orgStars func() int
+	}
+
+	// repoState is shared by the monitor loop and the retries of the star events.
+	repoState struct {
+		lock       sync.RWMutex
+		stargazers map[int64]Stargazer
+		dayStars   map[string]int
+		stars      int
+		milestone  int
+		releases   []Release
+		releasesAt time.Time
+		reported   map[string]bool
+		// failedTags are the tags that failed to look up the commits, not requested again.
+		failedTags map[string]bool
+	}
+)
 
 func NewMonitor(cfg Config, sender sender.Sender) Monitor {
 	return Monitor{
 		cfg:    cfg,
 		cli:    CreateClient(cfg.Token),
 		sender: sender,
+		state:  newRepoState(),
 	}
 }
 
+func newRepoState() *repoState {
+	return &repoState{
+		stargazers: make(map[int64]Stargazer),
+		dayStars:   make(map[string]int),
+		reported:   make(map[string]bool),
+		failedTags: make(map[string]bool),
+	}
+}
+
+// starCount returns the stars, which the org monitor reads from the other monitors.
+func (s *repoState) starCount() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.stars
+}
+
+func (s *repoState) setStars(stars int) {
+	s.lock.Lock()
+	s.stars = stars
+	s.lock.Unlock()
+}
+
 func (m Monitor) Start() {
 	owner, project, err := ParseRepo(m.cfg.Repo)
 	logx.Must(err)
-
-	stars, err := RequestAll(m.cli, owner, project)
-	logx.Must(err)
-	stargazers = stars
+	logx.Must(m.load(owner, project))
 
 	if m.cfg.Expect != nil {
 		_, err := time.Parse(expectDayLayout, m.cfg.Expect.Date)
@@ -61,16 +100,33 @@ func (m Monitor) Start() {
 	defer ticker.Stop()
 	for range ticker.C {
 		m.refresh(owner, project)
-		m.report()
+		report(m.sender)
 	}
 }
 
+func (m Monitor) load(owner, project string) error {
+	stars, err := RequestStargazers(m.cli, owner, project)
+	if err != nil {
+		return err
+	}
+
+	m.state.stargazers = stars
+	m.state.stars = len(stars)
+	if m.cfg.Milestone > 0 {
+		m.state.milestone = len(stars) / m.cfg.Milestone * m.cfg.Milestone
+	}
+	m.refreshReleases(owner, project)
+	m.updateStats(len(stars))
+	m.comparisons()
+	return nil
+}
+
 func (m Monitor) beginOfDay(t time.Time) time.Time {
 	year, month, day := t.Date()
 	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
 }
 
-func (m Monitor) calculateExpect(buf *strings.Builder, stars int) {
+func (m Monitor) calculateExpect(msg *sender.Message, stars int) {
 	if m.cfg.Expect == nil {
 		return
 	}
@@ -86,15 +142,24 @@ func (m Monitor) calculateExpect(buf *strings.Builder, stars int) {
 
 	diff := deadline.Sub(time.Now()).Hours() / 24
 	expect := float64(m.cfg.Expect.Stars-stars) / diff
-	fmt.Fprintf(buf, "\nexpect: %.2f per day", expect)
+	msg.AddField("expect", fmt.Sprintf("%.2f per day", expect))
 }
 
-func (m Monitor) compare(buf *strings.Builder, total int) {
+func (m Monitor) compare(msg *sender.Message, total int) {
+	for _, comp := range m.comparisons() {
+		_, project, _ := ParseRepo(comp.Repo)
+		msg.AddField(project, fmt.Sprintf("%d/%d", total-comp.Stars, comp.Stars))
+	}
+}
+
+// comparisons requests the stars of the comparison repos, and keeps them for the bot commands.
+func (m Monitor) comparisons() []Comparison {
+	var comps []Comparison
 	for _, comp := range m.cfg.Comparisons {
 		owner, project, err := ParseRepo(comp)
 		if err != nil {
 			logx.Error(err)
-			return
+			return nil
 		}
 
 		repo, _, err := m.cli.Repositories.Get(context.Background(), owner, project)
@@ -103,20 +168,42 @@ func (m Monitor) compare(buf *strings.Builder, total int) {
 			continue
 		}
 
-		fmt.Fprintf(buf, "\n%s: %d/%d", project, total-*repo.StargazersCount, *repo.StargazersCount)
+		comps = append(comps, Comparison{
+			Repo:  comp,
+			Stars: repo.GetStargazersCount(),
+		})
 	}
+
+	if len(comps) > 0 {
+		updateStats(m.cfg.Repo, func(rs *RepoStats) {
+			rs.Comparisons = comps
+		})
+	}
+
+	return comps
+}
+
+func (m Monitor) updateStats(total int) {
+	today := m.countsToday(total)
+	updateStats(m.cfg.Repo, func(rs *RepoStats) {
+		rs.Stars = total
+		rs.Today = today
+	})
 }
 
 func (m Monitor) countsToday(total int) int {
+	m.state.lock.RLock()
+	defer m.state.lock.RUnlock()
+
 	yesterday := time.Now().Add(-time.Hour * 24).Format(dayFormat)
-	if stars, ok := dayStars[yesterday]; ok {
+	if stars, ok := m.state.dayStars[yesterday]; ok {
 		return total - stars
 	}
 
 	var count int
 	bod := m.beginOfDay(time.Now())
-	for _, t := range stargazers {
-		if t.After(bod) {
+	for _, gazer := range m.state.stargazers {
+		if gazer.StarredAt.After(bod) {
 			count++
 		}
 	}
@@ -124,55 +211,64 @@ func (m Monitor) countsToday(total int) int {
 	return count
 }
 
-func (m Monitor) handleResponseError(err error, repo *github.Repository, k string, v time.Time) {
-	logx.Error(err)
+// classify tells why a stargazer disappeared. The user is nil if the account
+// is no longer accessible. Suspended is only reported with SuspendedAt set, the other errors,
+// like a 403 of the SSO enforcement, are returned for the caller to log and skip the event.
+func (m Monitor) classify(id int64) (EventType, *github.User, error) {
+	user, _, err := m.cli.Users.GetByID(context.Background(), id)
+	if err != nil {
+		var ve *github.ErrorResponse
+		if !errors.As(err, &ve) {
+			return "", nil, err
+		}
 
-	if !m.cfg.Verbose {
-		return
+		if ve.Response.StatusCode == http.StatusNotFound {
+			return DeletedEvent, nil, nil
+		}
+
+		return "", nil, err
 	}
 
-	switch ve := err.(type) {
-	case *github.ErrorResponse:
-		if ve.Response.StatusCode != http.StatusNotFound {
-			break
-		}
+	if user.SuspendedAt != nil {
+		return SuspendedEvent, user, nil
+	}
+
+	return UnstarEvent, user, nil
+}
 
-		var builder strings.Builder
-		fmt.Fprintln(&builder, "account deleted")
-		fmt.Fprintf(&builder, "stars: %d\n", *repo.StargazersCount)
-		fmt.Fprintf(&builder, "today: %d\n", m.countsToday(*repo.StargazersCount))
-		fmt.Fprintf(&builder, "user: %s\n", k)
-		fmt.Fprintf(&builder, "starAt: %s", v.Local().Format(unstarAtFormat))
-		m.calculateExpect(&builder, *repo.StargazersCount)
-		m.compare(&builder, *repo.StargazersCount)
-		fifo.Put(builder.String())
+func (m Monitor) orgSummary(msg *sender.Message) {
+	if m.orgStars == nil {
+		return
 	}
+
+	msg.AddField("repo", m.cfg.Repo)
+	msg.AddField("org stars", m.orgStars())
 }
 
 func (m Monitor) refresh(owner, project string) {
 	count, err := m.totalCount(owner, project)
 	if err != nil {
-		logx.Errorf("refresh - %s", err.Error())
+		reportError(m.cfg.Repo, "refresh", err)
 		return
 	}
 
 	logx.Infof("stars: %d", count)
 	if err := m.requestPage(owner, project, count, (count+pageSize-1)/pageSize); err != nil {
-		logx.Error(err)
+		reportError(m.cfg.Repo, "stargazers", err)
 	}
+	m.refreshReleases(owner, project)
 }
 
-func (m Monitor) report() {
+func report(s sender.Sender) {
 	for !fifo.Empty() {
 		val, ok := fifo.Take()
 		if !ok {
 			break
 		}
 
-		if err := m.sender.Send(val.(string)); err != nil {
-			fifo.Put(val)
+		// the senders retry by themselves, a message failed after that is dropped
+		if err := sender.SendMessage(s, val.(Event).message()); err != nil {
 			logx.Error(err)
-			break
 		}
 	}
 }
@@ -183,33 +279,49 @@ func (m Monitor) reportStarring(owner, project string, total int, gazer *github.
 	}
 
 	ensureOnce(func() error {
-		name, followers, err := m.requestNameFollowers(*gazer.User.Login)
+		user, err := RequestUser(m.cli, *gazer.User.Login)
 		if err != nil {
 			logx.Error(err)
 			return err
 		}
 
+		if len(m.cfg.Watchlist) > 0 {
+			reportVIP(m.cli, m.sender, m.cfg.Watchlist, m.cfg.Repo, user)
+		}
+		updateStats(m.cfg.Repo, func(rs *RepoStats) {
+			rs.addKol(user.GetLogin(), user.GetFollowers())
+		})
+
 		// refresh count, because users might star after fetching count
 		if count, err := m.totalCount(owner, project); err == nil {
 			total = count
 		}
 
-		var builder strings.Builder
-		fmt.Fprintf(&builder, "stars: %d\n", total)
-		fmt.Fprintf(&builder, "today: %d\n", m.countsToday(total))
-		fmt.Fprintf(&builder, "user: %s\n", *gazer.User.Login)
-		if len(name) > 0 {
-			fmt.Fprintf(&builder, "name: %s\n", name)
+		msg := sender.Message{
+			Title:  "new star",
+			Avatar: user.GetAvatarURL(),
+			Color:  sender.ColorGreen,
 		}
-		if followers > 0 {
-			fmt.Fprintf(&builder, "followers: %d\n", followers)
+		msg.AddField("stars", total)
+		msg.AddField("today", m.countsToday(total))
+		msg.AddField("user", *gazer.User.Login)
+		if len(user.GetName()) > 0 {
+			msg.AddField("name", user.GetName())
 		}
-		fmt.Fprintf(&builder, "time: %s", gazer.StarredAt.Time.Local().Format(starAtFormat))
-		m.compare(&builder, total)
-		m.calculateExpect(&builder, total)
-		text := builder.String()
-		fifo.Put(text)
-		logx.Infof("star-event: %s", text)
+		// the routes may match the users with few followers
+		msg.AddField("followers", user.GetFollowers())
+		msg.AddField("time", gazer.StarredAt.Time.Local().Format(starAtFormat))
+		m.compare(&msg, total)
+		m.calculateExpect(&msg, total)
+		m.annotateRelease(&msg)
+		m.orgSummary(&msg)
+		msg.AddLink("profile", user.GetHTMLURL())
+		fifo.Put(Event{
+			Type:    StarEvent,
+			Repo:    m.cfg.Repo,
+			Message: msg,
+		})
+		logx.Infof("star-event: %s", msg)
 
 		return nil
 	}, time.Minute)
@@ -226,13 +338,29 @@ func (m Monitor) requestPage(owner, project string, count, page int) error {
 	}
 
 	for _, gazer := range gazers {
-		id := *gazer.User.Login
-		if _, ok := stargazers[id]; ok {
-			continue
+		id := gazer.User.GetID()
+		login := gazer.User.GetLogin()
+		m.state.lock.Lock()
+		prev, ok := m.state.stargazers[id]
+		switch {
+		case !ok:
+			m.state.stargazers[id] = Stargazer{
+				Login:     login,
+				StarredAt: gazer.StarredAt.Time,
+			}
+		case prev.Login != login:
+			m.state.stargazers[id] = Stargazer{
+				Login:     login,
+				StarredAt: prev.StarredAt,
+			}
 		}
+		m.state.lock.Unlock()
 
-		stargazers[id] = gazer.StarredAt.Time
-		m.reportStarring(owner, project, count, gazer)
+		if !ok {
+			m.reportStarring(owner, project, count, gazer)
+		} else if prev.Login != login {
+			m.reportRenamed(count, prev.Login, login)
+		}
 	}
 
 	if len(gazers) > 0 && gazers[0].StarredAt.Time.After(m.beginOfDay(time.Now())) {
@@ -242,41 +370,90 @@ func (m Monitor) requestPage(owner, project string, count, page int) error {
 	return nil
 }
 
-func (m Monitor) requestNameFollowers(id string) (name string, followers int, err error) {
-	var user *github.User
-	user, err = RequestUser(m.cli, id)
-	if err != nil {
-		return
+func (m Monitor) reportDisappeared(typ EventType, total int, gazer Stargazer, user *github.User) {
+	msg := sender.Message{
+		Color: sender.ColorRed,
 	}
-
+	switch typ {
+	case DeletedEvent:
+		msg.Title = "account deleted"
+		msg.Color = sender.ColorGrey
+	case SuspendedEvent:
+		msg.Title = "account suspended"
+		msg.Color = sender.ColorGrey
+	default:
+		msg.Title = "unstar"
+	}
+	msg.AddField("stars", total)
+	msg.AddField("today", m.countsToday(total))
+	msg.AddField("user", gazer.Login)
 	if user != nil {
-		if user.Name != nil {
-			name = *user.Name
-		}
-		if user.Followers != nil {
-			followers = *user.Followers
+		msg.Avatar = user.GetAvatarURL()
+		if len(user.GetName()) > 0 {
+			msg.AddField("name", user.GetName())
 		}
+		// the routes may match the users with few followers
+		msg.AddField("followers", user.GetFollowers())
 	}
+	msg.AddField("starAt", gazer.StarredAt.Local().Format(unstarAtFormat))
+	m.compare(&msg, total)
+	m.calculateExpect(&msg, total)
+	m.orgSummary(&msg)
+	if user != nil {
+		msg.AddLink("profile", user.GetHTMLURL())
+	}
+	fifo.Put(Event{
+		Type:    typ,
+		Repo:    m.cfg.Repo,
+		Message: msg,
+	})
+}
 
-	return
+func (m Monitor) reportMilestone(repo *github.Repository) {
+	if m.cfg.Milestone <= 0 {
+		return
+	}
+
+	m.state.lock.Lock()
+	if repo.GetStargazersCount() < m.state.milestone+m.cfg.Milestone {
+		m.state.lock.Unlock()
+		return
+	}
+	milestone := repo.GetStargazersCount() / m.cfg.Milestone * m.cfg.Milestone
+	m.state.milestone = milestone
+	m.state.lock.Unlock()
+
+	msg := sender.Message{
+		Title: fmt.Sprintf("%d stars", milestone),
+		Color: sender.ColorOrange,
+	}
+	msg.AddField("repo", repo.GetFullName())
+	msg.AddField("stars", repo.GetStargazersCount())
+	msg.AddField("today", m.countsToday(repo.GetStargazersCount()))
+	m.orgSummary(&msg)
+	msg.AddLink("repo", repo.GetHTMLURL())
+	fifo.Put(Event{
+		Type:    MilestoneEvent,
+		Repo:    m.cfg.Repo,
+		Message: msg,
+	})
 }
 
-func (m Monitor) reportUnstar(repo *github.Repository, id string, name string, followers int, v time.Time) {
-	var builder strings.Builder
-	fmt.Fprintln(&builder, "unstar")
-	fmt.Fprintf(&builder, "stars: %d\n", *repo.StargazersCount)
-	fmt.Fprintf(&builder, "today: %d\n", m.countsToday(*repo.StargazersCount))
-	fmt.Fprintf(&builder, "user: %s\n", id)
-	if len(name) > 0 {
-		fmt.Fprintf(&builder, "name: %s\n", name)
-	}
-	if followers > 0 {
-		fmt.Fprintf(&builder, "followers: %d\n", followers)
-	}
-	fmt.Fprintf(&builder, "starAt: %s", v.Local().Format(unstarAtFormat))
-	m.compare(&builder, *repo.StargazersCount)
-	m.calculateExpect(&builder, *repo.StargazersCount)
-	fifo.Put(builder.String())
+func (m Monitor) reportRenamed(total int, from, to string) {
+	msg := sender.Message{
+		Title: "user renamed",
+		Color: sender.ColorBlue,
+	}
+	msg.AddField("stars", total)
+	msg.AddField("user", to)
+	msg.AddField("former", from)
+	m.orgSummary(&msg)
+	msg.AddLink("profile", "https://github.com/"+to)
+	fifo.Put(Event{
+		Type:    RenamedEvent,
+		Repo:    m.cfg.Repo,
+		Message: msg,
+	})
 }
 
 func (m Monitor) totalCount(owner, project string) (int, error) {
@@ -286,34 +463,63 @@ func (m Monitor) totalCount(owner, project string) (int, error) {
 	}
 
 	day := time.Now().Format(dayFormat)
-	prev := dayStars[day]
+	m.state.lock.RLock()
+	prev := m.state.dayStars[day]
+	m.state.lock.RUnlock()
 	if *repo.StargazersCount < prev {
-		stars, err := RequestAll(m.cli, owner, project)
+		stars, err := RequestStargazers(m.cli, owner, project)
 		if err != nil {
 			return 0, err
 		}
 
-		for k, v := range stargazers {
-			if _, ok := stars[k]; ok {
-				continue
-			}
+		m.state.lock.Lock()
+		prevStars := m.state.stargazers
+		m.state.stargazers = stars
+		m.state.lock.Unlock()
 
-			name, followers, err := m.requestNameFollowers(k)
+		gone, renamed := diffStargazers(prevStars, stars)
+		for _, id := range gone {
+			typ, user, err := m.classify(id)
 			if err != nil {
-				m.handleResponseError(err, repo, k, v)
+				logx.Error(err)
 				continue
 			}
 
-			m.reportUnstar(repo, k, name, followers, v)
+			m.reportDisappeared(typ, *repo.StargazersCount, prevStars[id], user)
+		}
+		for id, from := range renamed {
+			m.reportRenamed(*repo.StargazersCount, from, stars[id].Login)
 		}
-
-		stargazers = stars
 	}
-	dayStars[day] = *repo.StargazersCount
+	m.state.lock.Lock()
+	m.state.dayStars[day] = *repo.StargazersCount
+	m.state.stars = *repo.StargazersCount
+	m.state.lock.Unlock()
+	m.updateStats(*repo.StargazersCount)
+	m.reportMilestone(repo)
 
 	return *repo.StargazersCount, nil
 }
 
+// diffStargazers returns the IDs of the stargazers in prev that are missing in cur,
+// and the former logins of the ones who renamed, keyed by ID.
+func diffStargazers(prev, cur map[int64]Stargazer) (gone []int64, renamed map[int64]string) {
+	renamed = make(map[int64]string)
+	for id, gazer := range prev {
+		now, ok := cur[id]
+		if !ok {
+			gone = append(gone, id)
+			continue
+		}
+
+		if now.Login != gazer.Login {
+			renamed[id] = gazer.Login
+		}
+	}
+
+	return
+}
+
 func ensureOnce(fn func() error, interval time.Duration) {
 	if err := fn(); err == nil {
 		return
@@ -2,6 +2,10 @@ package gh
 
 import (
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -30,3 +34,64 @@ func TestEnsureOnce(t *testing.T) {
 	ensureOnce(fn, time.Millisecond*10)
 	assert.Equal(t, int32(1), atomic.LoadInt32(&count))
 }
+
+func TestDiffStargazers(t *testing.T) {
+	prev := map[int64]Stargazer{
+		1: {Login: "alice"},
+		2: {Login: "bob"},
+		3: {Login: "carol"},
+	}
+	cur := map[int64]Stargazer{
+		1: {Login: "alice"},
+		3: {Login: "caroline"},
+		4: {Login: "dave"},
+	}
+
+	gone, renamed := diffStargazers(prev, cur)
+	assert.ElementsMatch(t, []int64{2}, gone)
+	assert.Equal(t, map[int64]string{3: "carol"}, renamed)
+}
+
+func TestClassify(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, "/user/") {
+		case "1":
+			w.Write([]byte(`{"login": "alice"}`))
+		case "2":
+			w.Write([]byte(`{"login": "bob", "suspended_at": "2024-01-01T00:00:00Z"}`))
+		case "3":
+			w.WriteHeader(http.StatusNotFound)
+		case "4":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer svr.Close()
+
+	m := NewMonitor(Config{}, nil)
+	m.cli.BaseURL, _ = url.Parse(svr.URL + "/")
+	tests := []struct {
+		id    int64
+		typ   EventType
+		login string
+		err   bool
+	}{
+		{id: 1, typ: UnstarEvent, login: "alice"},
+		{id: 2, typ: SuspendedEvent, login: "bob"},
+		{id: 3, typ: DeletedEvent},
+		{id: 4, err: true},
+		{id: 5, err: true},
+	}
+	for _, test := range tests {
+		typ, user, err := m.classify(test.id)
+		if test.err {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.typ, typ)
+		assert.Equal(t, test.login, user.GetLogin())
+	}
+}
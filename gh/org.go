@@ -0,0 +1,224 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const orgType = "Organization"
+
+// OrgMonitor monitors all the public repos of an organization or a user,
+// and picks up the newly created ones periodically.
+type OrgMonitor struct {
+	cfg      Config
+	cli      *github.Client
+	sender   sender.Sender
+	lock     sync.RWMutex
+	monitors map[string]Monitor
+}
+
+func NewOrgMonitor(cfg Config, sender sender.Sender) *OrgMonitor {
+	return &OrgMonitor{
+		cfg:      cfg,
+		cli:      CreateClient(cfg.Token),
+		sender:   sender,
+		monitors: make(map[string]Monitor),
+	}
+}
+
+func (m *OrgMonitor) Start() {
+	logx.Must(m.discover(false))
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+	discover := time.NewTicker(m.cfg.Org.Discover)
+	defer discover.Stop()
+
+	for {
+		select {
+		case <-discover.C:
+			if err := m.discover(true); err != nil {
+				reportError(m.cfg.Org.Name, "discover", err)
+			}
+		case <-ticker.C:
+			for _, mon := range m.snapshot() {
+				owner, project, err := ParseRepo(mon.cfg.Repo)
+				if err != nil {
+					logx.Error(err)
+					continue
+				}
+
+				mon.refresh(owner, project)
+			}
+			report(m.sender)
+		}
+	}
+}
+
+func (m *OrgMonitor) add(repo *github.Repository) error {
+	cfg := m.cfg
+	cfg.Repo = repo.GetFullName()
+	mon := Monitor{
+		cfg:      cfg,
+		cli:      m.cli,
+		sender:   m.sender,
+		state:    newRepoState(),
+		orgStars: m.totalStars,
+	}
+	if err := mon.load(repo.GetOwner().GetLogin(), repo.GetName()); err != nil {
+		return err
+	}
+
+	mon.state.setStars(repo.GetStargazersCount())
+	m.lock.Lock()
+	m.monitors[cfg.Repo] = mon
+	m.lock.Unlock()
+
+	return nil
+}
+
+func (m *OrgMonitor) discover(announce bool) error {
+	repos, err := m.listRepos()
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range repos {
+		if m.monitored(repo.GetFullName()) || !m.cfg.Org.matches(repo) {
+			continue
+		}
+
+		logx.Infof("start monitoring %s", repo.GetFullName())
+		if err := m.add(repo); err != nil {
+			logx.Error(err)
+			continue
+		}
+
+		if announce {
+			msg := sender.Message{
+				Title: "new repo",
+				Color: sender.ColorBlue,
+			}
+			msg.AddField("repo", repo.GetFullName())
+			msg.AddField("stars", repo.GetStargazersCount())
+			msg.AddField("org stars", m.totalStars())
+			msg.AddLink("repo", repo.GetHTMLURL())
+			fifo.Put(Event{
+				Type:    NewRepoEvent,
+				Repo:    repo.GetFullName(),
+				Message: msg,
+			})
+		}
+	}
+
+	return nil
+}
+
+func (m *OrgMonitor) listRepos() ([]*github.Repository, error) {
+	user, _, err := m.cli.Users.Get(context.Background(), m.cfg.Org.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []*github.Repository
+	var page = 1
+	for {
+		var items []*github.Repository
+		var resp *github.Response
+		opts := github.ListOptions{
+			Page:    page,
+			PerPage: pageSize,
+		}
+		if user.GetType() == orgType {
+			items, resp, err = m.cli.Repositories.ListByOrg(context.Background(), m.cfg.Org.Name,
+				&github.RepositoryListByOrgOptions{
+					Type:        "public",
+					ListOptions: opts,
+				})
+		} else {
+			items, resp, err = m.cli.Repositories.List(context.Background(), m.cfg.Org.Name,
+				&github.RepositoryListOptions{
+					Type:        "owner",
+					ListOptions: opts,
+				})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repos of %s, error: %v", m.cfg.Org.Name, err)
+		}
+
+		repos = append(repos, items...)
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	return repos, nil
+}
+
+func (m *OrgMonitor) monitored(repo string) bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	_, ok := m.monitors[repo]
+	return ok
+}
+
+func (m *OrgMonitor) snapshot() []Monitor {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	monitors := make([]Monitor, 0, len(m.monitors))
+	for _, mon := range m.monitors {
+		monitors = append(monitors, mon)
+	}
+	sort.Slice(monitors, func(i, j int) bool {
+		return monitors[i].cfg.Repo < monitors[j].cfg.Repo
+	})
+
+	return monitors
+}
+
+func (m *OrgMonitor) totalStars() int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var total int
+	for _, mon := range m.monitors {
+		total += mon.state.starCount()
+	}
+
+	return total
+}
+
+func (o *Org) matches(repo *github.Repository) bool {
+	if repo.GetPrivate() || repo.GetStargazersCount() < o.MinStars {
+		return false
+	}
+
+	name := repo.GetName()
+	if len(o.Include) > 0 && !matchAny(o.Include, name) {
+		return false
+	}
+
+	return !matchAny(o.Exclude, name)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+
+	return false
+}
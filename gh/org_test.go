@@ -0,0 +1,95 @@
+package gh
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrgMatches(t *testing.T) {
+	org := Org{
+		Include:  []string{"go-*", "goctl"},
+		Exclude:  []string{"*-example"},
+		MinStars: 10,
+	}
+
+	tests := []struct {
+		name    string
+		stars   int
+		private bool
+		expect  bool
+	}{
+		{name: "go-zero", stars: 100, expect: true},
+		{name: "goctl", stars: 10, expect: true},
+		{name: "go-queue", stars: 9, expect: false},
+		{name: "go-zero-example", stars: 100, expect: false},
+		{name: "zero-doc", stars: 100, expect: false},
+		{name: "go-private", stars: 100, private: true, expect: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &github.Repository{
+				Name:            github.String(test.name),
+				StargazersCount: github.Int(test.stars),
+				Private:         github.Bool(test.private),
+			}
+			assert.Equal(t, test.expect, org.matches(repo))
+		})
+	}
+}
+
+func TestOrgDiscover(t *testing.T) {
+	takeEvents()
+	repos := `[
+		{"name": "go-zero", "full_name": "zeromicro/go-zero", "stargazers_count": 100, "owner": {"login": "zeromicro"}},
+		{"name": "go-queue", "full_name": "zeromicro/go-queue", "stargazers_count": 5, "owner": {"login": "zeromicro"}}
+	]`
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/zeromicro":
+			w.Write([]byte(`{"login": "zeromicro", "type": "Organization"}`))
+		case "/orgs/zeromicro/repos":
+			w.Write([]byte(repos))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer svr.Close()
+
+	m := NewOrgMonitor(Config{
+		Org: &Org{
+			Name:     "zeromicro",
+			MinStars: 10,
+		},
+		Interval: time.Minute,
+	}, nil)
+	m.cli.BaseURL, _ = url.Parse(svr.URL + "/")
+	assert.NoError(t, m.discover(false))
+	assert.True(t, m.monitored("zeromicro/go-zero"))
+	assert.False(t, m.monitored("zeromicro/go-queue"))
+	assert.Equal(t, 100, m.totalStars())
+	assert.Empty(t, takeEvents())
+
+	repos = `[
+		{"name": "go-zero", "full_name": "zeromicro/go-zero", "stargazers_count": 100, "owner": {"login": "zeromicro"}},
+		{"name": "go-queue", "full_name": "zeromicro/go-queue", "stargazers_count": 5, "owner": {"login": "zeromicro"}},
+		{"name": "goctl", "full_name": "zeromicro/goctl", "stargazers_count": 20, "owner": {"login": "zeromicro"},
+			"html_url": "https://github.com/zeromicro/goctl"}
+	]`
+	assert.NoError(t, m.discover(true))
+	assert.Len(t, m.snapshot(), 2)
+	assert.Equal(t, 120, m.totalStars())
+	events := takeEvents()
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, NewRepoEvent, events[0].Type)
+		assert.Equal(t, "zeromicro/goctl", events[0].Repo)
+		assert.Equal(t, "new repo\nrepo: zeromicro/goctl\nstars: 20\norg stars: 120\n"+
+			"repo: https://github.com/zeromicro/goctl", events[0].Message.String())
+	}
+}
@@ -0,0 +1,222 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type (
+	Release struct {
+		Tag         string
+		PublishedAt time.Time
+	}
+
+	ReleaseVelocity struct {
+		Release
+		Before int
+		After  int
+	}
+)
+
+// RequestReleases returns the published releases and the tags without releases, ordered by time.
+// The tags in known are not requested again, because a commit request is needed for each tag.
+// The tags failed to look up are recorded in failed if not nil, and skipped afterwards.
+func RequestReleases(cli *github.Client, owner, project string, known map[string]Release,
+	failed map[string]bool) ([]Release, error) {
+	releases := make(map[string]Release)
+	var page = 1
+	for {
+		items, resp, err := cli.Repositories.ListReleases(context.Background(),
+			owner, project, &github.ListOptions{
+				Page:    page,
+				PerPage: pageSize,
+			})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch releases, error: %v", err)
+		}
+
+		for _, item := range items {
+			if item.GetDraft() || item.PublishedAt == nil {
+				continue
+			}
+
+			releases[item.GetTagName()] = Release{
+				Tag:         item.GetTagName(),
+				PublishedAt: item.GetPublishedAt().Time,
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	page = 1
+	for {
+		tags, resp, err := cli.Repositories.ListTags(context.Background(),
+			owner, project, &github.ListOptions{
+				Page:    page,
+				PerPage: pageSize,
+			})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch tags, error: %v", err)
+		}
+
+		for _, tag := range tags {
+			name := tag.GetName()
+			if _, ok := releases[name]; ok {
+				continue
+			}
+
+			if release, ok := known[name]; ok {
+				releases[name] = release
+				continue
+			}
+			if failed[name] {
+				continue
+			}
+
+			commit, _, err := cli.Git.GetCommit(context.Background(), owner, project, tag.GetCommit().GetSHA())
+			if err != nil {
+				logx.Error(err)
+				if failed != nil {
+					failed[name] = true
+				}
+				continue
+			}
+
+			releases[name] = Release{
+				Tag:         name,
+				PublishedAt: commit.GetCommitter().GetDate(),
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	result := make([]Release, 0, len(releases))
+	for _, release := range releases {
+		result = append(result, release)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PublishedAt.Before(result[j].PublishedAt)
+	})
+
+	return result, nil
+}
+
+// StarVelocity counts the stars in the window before and after each release.
+func StarVelocity(stars []time.Time, releases []Release, window time.Duration) []ReleaseVelocity {
+	velocities := make([]ReleaseVelocity, 0, len(releases))
+	for _, release := range releases {
+		velocity := ReleaseVelocity{
+			Release: release,
+		}
+		for _, star := range stars {
+			switch {
+			case star.Before(release.PublishedAt) && !star.Before(release.PublishedAt.Add(-window)):
+				velocity.Before++
+			case !star.Before(release.PublishedAt) && star.Before(release.PublishedAt.Add(window)):
+				velocity.After++
+			}
+		}
+		velocities = append(velocities, velocity)
+	}
+
+	return velocities
+}
+
+func (v ReleaseVelocity) Format(window time.Duration) string {
+	hours := int(window.Hours())
+	return fmt.Sprintf("+%d stars in the %dh after %s\n+%d stars in the %dh before",
+		v.After, hours, v.Tag, v.Before, hours)
+}
+
+// annotateRelease runs in the retries of the star events, so it reads the state under the lock.
+func (m Monitor) annotateRelease(msg *sender.Message) {
+	if m.cfg.Releases == nil {
+		return
+	}
+
+	m.state.lock.RLock()
+	if len(m.state.releases) == 0 {
+		m.state.lock.RUnlock()
+		return
+	}
+	latest := m.state.releases[len(m.state.releases)-1]
+	var count int
+	for _, gazer := range m.state.stargazers {
+		if !gazer.StarredAt.Before(latest.PublishedAt) {
+			count++
+		}
+	}
+	m.state.lock.RUnlock()
+
+	elapsed := time.Since(latest.PublishedAt)
+	if elapsed >= m.cfg.Releases.Window {
+		return
+	}
+
+	msg.AddField("release", fmt.Sprintf("+%d stars in the %dh after %s", count, int(elapsed.Hours())+1, latest.Tag))
+}
+
+func (m Monitor) refreshReleases(owner, project string) {
+	if m.cfg.Releases == nil || time.Since(m.state.releasesAt) < m.cfg.Releases.Interval {
+		return
+	}
+
+	known := make(map[string]Release)
+	for _, release := range m.state.releases {
+		known[release.Tag] = release
+	}
+	releases, err := RequestReleases(m.cli, owner, project, known, m.state.failedTags)
+	if err != nil {
+		reportError(m.cfg.Repo, "releases", err)
+		return
+	}
+
+	// don't report the releases that were over before starting
+	initial := m.state.releasesAt.IsZero()
+	m.state.lock.Lock()
+	m.state.releases = releases
+	m.state.lock.Unlock()
+	m.state.releasesAt = time.Now()
+	for _, release := range releases {
+		if m.state.reported[release.Tag] || time.Since(release.PublishedAt) < m.cfg.Releases.Window {
+			continue
+		}
+
+		m.state.reported[release.Tag] = true
+		if initial {
+			continue
+		}
+
+		m.state.lock.RLock()
+		stars := make([]time.Time, 0, len(m.state.stargazers))
+		for _, gazer := range m.state.stargazers {
+			stars = append(stars, gazer.StarredAt)
+		}
+		m.state.lock.RUnlock()
+		velocity := StarVelocity(stars, []Release{release}, m.cfg.Releases.Window)[0]
+		fifo.Put(Event{
+			Type: ReleaseEvent,
+			Repo: m.cfg.Repo,
+			Message: sender.Message{
+				Title: "release " + release.Tag,
+				Text:  velocity.Format(m.cfg.Releases.Window),
+				Color: sender.ColorBlue,
+			},
+		})
+	}
+}
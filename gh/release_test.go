@@ -0,0 +1,69 @@
+package gh
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStarVelocity(t *testing.T) {
+	published := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	stars := []time.Time{
+		published.Add(-time.Hour * 80),
+		published.Add(-time.Hour * 10),
+		published,
+		published.Add(time.Hour),
+		published.Add(time.Hour * 71),
+		published.Add(time.Hour * 72),
+	}
+
+	velocities := StarVelocity(stars, []Release{
+		{
+			Tag:         "v1.7.0",
+			PublishedAt: published,
+		},
+	}, time.Hour*72)
+	assert.Equal(t, []ReleaseVelocity{
+		{
+			Release: Release{
+				Tag:         "v1.7.0",
+				PublishedAt: published,
+			},
+			Before: 1,
+			After:  3,
+		},
+	}, velocities)
+	assert.Equal(t, "+3 stars in the 72h after v1.7.0\n+1 stars in the 72h before",
+		velocities[0].Format(time.Hour*72))
+}
+
+func TestRequestReleasesFailedTags(t *testing.T) {
+	var commits int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/zeromicro/go-zero/releases":
+			w.Write([]byte(`[]`))
+		case "/repos/zeromicro/go-zero/tags":
+			w.Write([]byte(`[{"name": "v1.0.0", "commit": {"sha": "abc"}}]`))
+		default:
+			commits++
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer svr.Close()
+
+	cli := CreateClient("")
+	cli.BaseURL, _ = url.Parse(svr.URL + "/")
+	failed := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		releases, err := RequestReleases(cli, "zeromicro", "go-zero", nil, failed)
+		assert.NoError(t, err)
+		assert.Empty(t, releases)
+	}
+	assert.Equal(t, 1, commits)
+	assert.True(t, failed["v1.0.0"])
+}
@@ -0,0 +1,92 @@
+package gh
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maxKols = 10
+
+var stats = struct {
+	sync.RWMutex
+	repos map[string]*RepoStats
+}{
+	repos: make(map[string]*RepoStats),
+}
+
+type (
+	// RepoStats is the in-memory state of a monitored repo, for the bot commands.
+	RepoStats struct {
+		Repo        string
+		Stars       int
+		Today       int
+		Comparisons []Comparison
+		// Kols are the stargazers with the most followers since the monitor started.
+		Kols      []Kol
+		UpdatedAt time.Time
+	}
+
+	Comparison struct {
+		Repo  string
+		Stars int
+	}
+
+	Kol struct {
+		Login     string
+		Followers int
+	}
+)
+
+// Stats returns the stats of all the monitored repos, sorted by repo.
+func Stats() []RepoStats {
+	stats.RLock()
+	defer stats.RUnlock()
+
+	all := make([]RepoStats, 0, len(stats.repos))
+	for _, rs := range stats.repos {
+		s := *rs
+		s.Comparisons = slices.Clone(rs.Comparisons)
+		s.Kols = slices.Clone(rs.Kols)
+		all = append(all, s)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Repo < all[j].Repo
+	})
+
+	return all
+}
+
+func updateStats(repo string, fn func(rs *RepoStats)) {
+	stats.Lock()
+	defer stats.Unlock()
+
+	rs, ok := stats.repos[repo]
+	if !ok {
+		rs = &RepoStats{
+			Repo: repo,
+		}
+		stats.repos[repo] = rs
+	}
+	fn(rs)
+	rs.UpdatedAt = time.Now()
+}
+
+// addKol keeps the top stargazers by followers, a renamed user is kept once.
+func (rs *RepoStats) addKol(login string, followers int) {
+	rs.Kols = slices.DeleteFunc(rs.Kols, func(kol Kol) bool {
+		return strings.EqualFold(kol.Login, login)
+	})
+	rs.Kols = append(rs.Kols, Kol{
+		Login:     login,
+		Followers: followers,
+	})
+	sort.SliceStable(rs.Kols, func(i, j int) bool {
+		return rs.Kols[i].Followers > rs.Kols[j].Followers
+	})
+	if len(rs.Kols) > maxKols {
+		rs.Kols = rs.Kols[:maxKols]
+	}
+}
@@ -0,0 +1,20 @@
+package gh
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddKol(t *testing.T) {
+	var rs RepoStats
+	for i := 0; i < maxKols+2; i++ {
+		rs.addKol(fmt.Sprintf("user%d", i), i)
+	}
+	rs.addKol("USER0", 100)
+
+	assert.Len(t, rs.Kols, maxKols)
+	assert.Equal(t, Kol{Login: "USER0", Followers: 100}, rs.Kols[0])
+	assert.Equal(t, 11, rs.Kols[1].Followers)
+}
@@ -0,0 +1,290 @@
+package gh
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const perDay = "day"
+
+type (
+	// TrafficMonitor collects the traffic of the repo, which GitHub only keeps for 14 days,
+	// and stores it permanently.
+	TrafficMonitor struct {
+		cfg     Config
+		cli     *github.Client
+		sender  sender.Sender
+		history *trafficHistory
+	}
+
+	trafficHistory struct {
+		Views  map[string]trafficCount `json:"views"`
+		Clones map[string]trafficCount `json:"clones"`
+		// Referrers and Paths are the snapshots of the 14 days totals, keyed by collecting day.
+		Referrers map[string][]trafficItem `json:"referrers"`
+		Paths     map[string][]trafficItem `json:"paths"`
+		// Peaks are the highest 14 days views of the referrers, to alert once they cross ReferrerAlert.
+		Peaks    map[string]int `json:"peaks"`
+		Digested string         `json:"digested"`
+	}
+
+	trafficCount struct {
+		Count   int `json:"count"`
+		Uniques int `json:"uniques"`
+	}
+
+	trafficItem struct {
+		Name    string `json:"name"`
+		Title   string `json:"title,omitempty"`
+		Count   int    `json:"count"`
+		Uniques int    `json:"uniques"`
+	}
+)
+
+func NewTrafficMonitor(cfg Config, sender sender.Sender) *TrafficMonitor {
+	return &TrafficMonitor{
+		cfg:    cfg,
+		cli:    CreateClient(cfg.Token),
+		sender: sender,
+		history: &trafficHistory{
+			Views:     make(map[string]trafficCount),
+			Clones:    make(map[string]trafficCount),
+			Referrers: make(map[string][]trafficItem),
+			Paths:     make(map[string][]trafficItem),
+			Peaks:     make(map[string]int),
+		},
+	}
+}
+
+func (m *TrafficMonitor) Start() {
+	owner, project, err := ParseRepo(m.cfg.Repo)
+	logx.Must(err)
+	logx.Must(m.load())
+
+	m.collect(owner, project)
+	ticker := time.NewTicker(m.cfg.Traffic.Interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		m.collect(owner, project)
+	}
+}
+
+func (m *TrafficMonitor) collect(owner, project string) {
+	if err := m.fetch(owner, project); err != nil {
+		reportError(m.cfg.Repo, "traffic", err)
+		report(m.sender)
+		return
+	}
+
+	m.digest()
+	if err := m.save(); err != nil {
+		logx.Error(err)
+	}
+	report(m.sender)
+}
+
+func (m *TrafficMonitor) digest() {
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format(expectDayLayout)
+	if m.history.Digested == yesterday {
+		return
+	}
+
+	// GitHub has no data of yesterday yet, or there was no traffic
+	views, ok := m.history.Views[yesterday]
+	clones, cok := m.history.Clones[yesterday]
+	if !ok && !cok {
+		return
+	}
+
+	msg := sender.Message{
+		Title: "traffic " + yesterday,
+		Color: sender.ColorBlue,
+	}
+	msg.AddField("views", fmt.Sprintf("%d, uniques: %d", views.Count, views.Uniques))
+	msg.AddField("clones", fmt.Sprintf("%d, uniques: %d", clones.Count, clones.Uniques))
+	referrers := m.latest(m.history.Referrers)
+	if len(referrers) > m.cfg.Traffic.TopReferrers {
+		referrers = referrers[:m.cfg.Traffic.TopReferrers]
+	}
+	if len(referrers) > 0 {
+		lines := []string{"top referrers (14 days):"}
+		for _, ref := range referrers {
+			lines = append(lines, fmt.Sprintf("%s: %d, uniques: %d", ref.Name, ref.Count, ref.Uniques))
+		}
+		msg.Text = strings.Join(lines, "\n")
+	}
+
+	fifo.Put(Event{
+		Type:    TrafficEvent,
+		Repo:    m.cfg.Repo,
+		Message: msg,
+	})
+	m.history.Digested = yesterday
+}
+
+func (m *TrafficMonitor) fetch(owner, project string) error {
+	ctx := context.Background()
+	views, _, err := m.cli.Repositories.ListTrafficViews(ctx, owner, project,
+		&github.TrafficBreakdownOptions{Per: perDay})
+	if err != nil {
+		return err
+	}
+	for _, view := range views.Views {
+		m.history.Views[view.GetTimestamp().UTC().Format(expectDayLayout)] = trafficCount{
+			Count:   view.GetCount(),
+			Uniques: view.GetUniques(),
+		}
+	}
+
+	clones, _, err := m.cli.Repositories.ListTrafficClones(ctx, owner, project,
+		&github.TrafficBreakdownOptions{Per: perDay})
+	if err != nil {
+		return err
+	}
+	for _, clone := range clones.Clones {
+		m.history.Clones[clone.GetTimestamp().UTC().Format(expectDayLayout)] = trafficCount{
+			Count:   clone.GetCount(),
+			Uniques: clone.GetUniques(),
+		}
+	}
+
+	today := time.Now().UTC().Format(expectDayLayout)
+	refs, _, err := m.cli.Repositories.ListTrafficReferrers(ctx, owner, project)
+	if err != nil {
+		return err
+	}
+	var referrers []trafficItem
+	for _, ref := range refs {
+		referrers = append(referrers, trafficItem{
+			Name:    ref.GetReferrer(),
+			Count:   ref.GetCount(),
+			Uniques: ref.GetUniques(),
+		})
+	}
+	m.alertReferrers(referrers)
+	m.history.Referrers[today] = referrers
+
+	paths, _, err := m.cli.Repositories.ListTrafficPaths(ctx, owner, project)
+	if err != nil {
+		return err
+	}
+	var items []trafficItem
+	for _, path := range paths {
+		items = append(items, trafficItem{
+			Name:    path.GetPath(),
+			Title:   path.GetTitle(),
+			Count:   path.GetCount(),
+			Uniques: path.GetUniques(),
+		})
+	}
+	m.history.Paths[today] = items
+
+	return nil
+}
+
+// alertReferrers reports the referrers that suddenly drive significant traffic,
+// which were never seen before or stayed below ReferrerAlert until now.
+func (m *TrafficMonitor) alertReferrers(referrers []trafficItem) {
+	// nothing to compare with on the first collection
+	first := len(m.history.Referrers) == 0
+	for _, ref := range referrers {
+		peak := m.history.Peaks[ref.Name]
+		m.history.Peaks[ref.Name] = max(peak, ref.Count)
+		if first || peak >= m.cfg.Traffic.ReferrerAlert || ref.Count < m.cfg.Traffic.ReferrerAlert {
+			continue
+		}
+
+		msg := sender.Message{
+			Title: "new referrer",
+			Color: sender.ColorOrange,
+		}
+		msg.AddField("referrer", ref.Name)
+		msg.AddField("views", ref.Count)
+		msg.AddField("uniques", ref.Uniques)
+		fifo.Put(Event{
+			Type:    ReferrerEvent,
+			Repo:    m.cfg.Repo,
+			Message: msg,
+		})
+	}
+}
+
+func (m *TrafficMonitor) latest(snapshots map[string][]trafficItem) []trafficItem {
+	var day string
+	for k := range snapshots {
+		if k > day {
+			day = k
+		}
+	}
+
+	items := append([]trafficItem(nil), snapshots[day]...)
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Count > items[j].Count
+	})
+
+	return items
+}
+
+func (m *TrafficMonitor) load() error {
+	content, err := os.ReadFile(m.cfg.Traffic.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(content, m.history); err != nil {
+		return err
+	}
+
+	// the maps stored as null are loaded as nil
+	if m.history.Views == nil {
+		m.history.Views = make(map[string]trafficCount)
+	}
+	if m.history.Clones == nil {
+		m.history.Clones = make(map[string]trafficCount)
+	}
+	if m.history.Referrers == nil {
+		m.history.Referrers = make(map[string][]trafficItem)
+	}
+	if m.history.Paths == nil {
+		m.history.Paths = make(map[string][]trafficItem)
+	}
+	// the histories stored before the peaks take the peaks from the snapshots
+	if m.history.Peaks == nil {
+		m.history.Peaks = make(map[string]int)
+		for _, items := range m.history.Referrers {
+			for _, item := range items {
+				m.history.Peaks[item.Name] = max(m.history.Peaks[item.Name], item.Count)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *TrafficMonitor) save() error {
+	content, err := json.MarshalIndent(m.history, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := m.cfg.Traffic.Path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, m.cfg.Traffic.Path)
+}
@@ -0,0 +1,109 @@
+package gh
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrafficLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traffic.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"views": null, "referrers": null}`), 0o644))
+
+	cfg := Config{Traffic: &Traffic{Path: path}}
+	m := NewTrafficMonitor(cfg, nil)
+	assert.NoError(t, m.load())
+	m.history.Views["2024-01-01"] = trafficCount{Count: 10, Uniques: 3}
+	m.history.Referrers["2024-01-01"] = []trafficItem{{Name: "google.com", Count: 5, Uniques: 2}}
+	assert.NoError(t, m.save())
+
+	loaded := NewTrafficMonitor(cfg, nil)
+	assert.NoError(t, loaded.load())
+	assert.Equal(t, m.history, loaded.history)
+}
+
+func TestTrafficDigest(t *testing.T) {
+	takeEvents()
+	m := NewTrafficMonitor(Config{
+		Repo:    "zeromicro/go-zero",
+		Traffic: &Traffic{TopReferrers: 1},
+	}, nil)
+	m.digest()
+	assert.Empty(t, takeEvents())
+
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format(expectDayLayout)
+	m.history.Views[yesterday] = trafficCount{Count: 10, Uniques: 3}
+	m.history.Referrers[yesterday] = []trafficItem{
+		{Name: "google.com", Count: 5, Uniques: 2},
+		{Name: "github.com", Count: 8, Uniques: 4},
+	}
+	m.digest()
+	m.digest()
+	events := takeEvents()
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, TrafficEvent, events[0].Type)
+		assert.Equal(t, "traffic "+yesterday+"\nviews: 10, uniques: 3\nclones: 0, uniques: 0\n"+
+			"top referrers (14 days):\ngithub.com: 8, uniques: 4", events[0].Message.String())
+	}
+	assert.Equal(t, yesterday, m.history.Digested)
+}
+
+func TestAlertReferrers(t *testing.T) {
+	takeEvents()
+	m := NewTrafficMonitor(Config{
+		Repo:    "zeromicro/go-zero",
+		Traffic: &Traffic{ReferrerAlert: 50},
+	}, nil)
+	m.alertReferrers([]trafficItem{{Name: "news.ycombinator.com", Count: 100}})
+	assert.Empty(t, takeEvents())
+
+	m.history.Referrers["2024-01-01"] = []trafficItem{{Name: "news.ycombinator.com", Count: 100}}
+	m.alertReferrers([]trafficItem{
+		{Name: "news.ycombinator.com", Count: 120},
+		{Name: "reddit.com", Count: 10},
+		{Name: "lobste.rs", Count: 60, Uniques: 40},
+	})
+	events := takeEvents()
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, ReferrerEvent, events[0].Type)
+		assert.Equal(t, "new referrer\nreferrer: lobste.rs\nviews: 60\nuniques: 40",
+			events[0].Message.String())
+	}
+}
+
+func TestAlertReferrersGrowing(t *testing.T) {
+	takeEvents()
+	m := NewTrafficMonitor(Config{
+		Repo:    "zeromicro/go-zero",
+		Traffic: &Traffic{ReferrerAlert: 50},
+	}, nil)
+	m.history.Referrers["2024-01-01"] = []trafficItem{{Name: "google.com", Count: 5}}
+	m.alertReferrers([]trafficItem{{Name: "reddit.com", Count: 3}})
+	assert.Empty(t, takeEvents())
+
+	m.alertReferrers([]trafficItem{{Name: "reddit.com", Count: 500}})
+	events := takeEvents()
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "new referrer\nreferrer: reddit.com\nviews: 500\nuniques: 0",
+			events[0].Message.String())
+	}
+
+	m.alertReferrers([]trafficItem{{Name: "reddit.com", Count: 600}})
+	assert.Empty(t, takeEvents())
+}
+
+func takeEvents() []Event {
+	var events []Event
+	for !fifo.Empty() {
+		val, ok := fifo.Take()
+		if !ok {
+			break
+		}
+		events = append(events, val.(Event))
+	}
+
+	return events
+}
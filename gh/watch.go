@@ -0,0 +1,193 @@
+package gh
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/zeromicro/go-zero/core/collection"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// latestStargazers are the stargazers checked on each poll, more than a poll interval brings
+	latestStargazers = 100
+	userOrgsExpiry   = time.Hour
+)
+
+// userOrgs caches the organizations of the users looked up for the watchlist.
+var userOrgs = func() *collection.Cache {
+	cache, err := collection.NewCache(userOrgsExpiry)
+	logx.Must(err)
+	return cache
+}()
+
+// WatchMonitor watches the comparison repos for the stars from the users on the watchlist.
+type WatchMonitor struct {
+	cfg    Config
+	cli    *github.Client
+	sender sender.Sender
+	seen   map[string]map[int64]struct{}
+}
+
+func NewWatchMonitor(cfg Config, sender sender.Sender) *WatchMonitor {
+	return &WatchMonitor{
+		cfg:    cfg,
+		cli:    CreateClient(cfg.Token),
+		sender: sender,
+		seen:   make(map[string]map[int64]struct{}),
+	}
+}
+
+func (m *WatchMonitor) Start() {
+	for _, repo := range m.cfg.Comparisons {
+		if err := m.poll(repo, false); err != nil {
+			logx.Error(err)
+		}
+	}
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		for _, repo := range m.cfg.Comparisons {
+			if err := m.poll(repo, true); err != nil {
+				reportError(repo, "watch", err)
+			}
+		}
+	}
+}
+
+// poll checks the latest stargazers, which works for the repos of any size.
+func (m *WatchMonitor) poll(repo string, notify bool) error {
+	owner, project, err := ParseRepo(repo)
+	if err != nil {
+		return err
+	}
+
+	gazers, err := RequestLatestStargazers(m.cli, owner, project, latestStargazers)
+	if err != nil {
+		return err
+	}
+
+	seen, ok := m.seen[repo]
+	if !ok {
+		seen = make(map[int64]struct{})
+		m.seen[repo] = seen
+	}
+
+	for _, gazer := range gazers {
+		id := gazer.User.GetID()
+		if _, ok := seen[id]; ok {
+			continue
+		}
+
+		seen[id] = struct{}{}
+		if !notify {
+			continue
+		}
+
+		user, err := RequestUser(m.cli, gazer.User.GetLogin())
+		if err != nil {
+			logx.Error(err)
+			continue
+		}
+
+		reportVIP(m.cli, m.sender, m.cfg.Watchlist, repo, user)
+	}
+
+	return nil
+}
+
+// reportVIP sends the notification right away if the user is on the watchlist,
+// and queues it if sending fails.
+func reportVIP(cli *github.Client, s sender.Sender, watchlist []string, repo string, user *github.User) {
+	entry, ok := matchWatchlist(cli, watchlist, user)
+	if !ok {
+		return
+	}
+
+	msg := sender.Message{
+		Title:  "VIP star",
+		Avatar: user.GetAvatarURL(),
+		Color:  sender.ColorOrange,
+	}
+	msg.AddField("repo", repo)
+	msg.AddField("user", user.GetLogin())
+	msg.AddField("watch", entry)
+	if len(user.GetName()) > 0 {
+		msg.AddField("name", user.GetName())
+	}
+	if len(user.GetCompany()) > 0 {
+		msg.AddField("company", user.GetCompany())
+	}
+	if len(user.GetLocation()) > 0 {
+		msg.AddField("location", user.GetLocation())
+	}
+	if len(user.GetBlog()) > 0 {
+		msg.AddField("blog", user.GetBlog())
+	}
+	msg.AddField("followers", user.GetFollowers())
+	msg.AddLink("profile", user.GetHTMLURL())
+	event := Event{
+		Type:    VIPEvent,
+		Repo:    repo,
+		Message: msg,
+	}
+	logx.Infof("vip-event: %s", msg)
+
+	if err := sender.SendMessage(s, event.message()); err != nil {
+		logx.Error(err)
+		fifo.Put(event)
+	}
+}
+
+// matchWatchlist returns the watchlist entry that matches the user.
+func matchWatchlist(cli *github.Client, watchlist []string, user *github.User) (string, bool) {
+	if entry, ok := matchEntries(watchlist, user, nil); ok {
+		return entry, true
+	}
+
+	logins, err := userOrgs.Take(user.GetLogin(), func() (any, error) {
+		orgs, _, err := cli.Organizations.List(context.Background(), user.GetLogin(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var logins []string
+		for _, org := range orgs {
+			logins = append(logins, org.GetLogin())
+		}
+		return logins, nil
+	})
+	if err != nil {
+		logx.Error(err)
+		return "", false
+	}
+
+	return matchEntries(watchlist, user, logins.([]string))
+}
+
+func matchEntries(watchlist []string, user *github.User, orgs []string) (string, bool) {
+	company := strings.ToLower(strings.TrimSpace(user.GetCompany()))
+	for _, entry := range watchlist {
+		if strings.EqualFold(entry, user.GetLogin()) {
+			return entry, true
+		}
+
+		name := strings.ToLower(entry)
+		if len(company) > 0 && (company == name || strings.Contains(company, "@"+name)) {
+			return entry, true
+		}
+
+		for _, org := range orgs {
+			if strings.EqualFold(entry, org) {
+				return entry, true
+			}
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,124 @@
+package gh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"stargazers/sender"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchEntries(t *testing.T) {
+	watchlist := []string{"kevwan", "google"}
+
+	tests := []struct {
+		name    string
+		login   string
+		company string
+		orgs    []string
+		entry   string
+		ok      bool
+	}{
+		{name: "login", login: "KevWan", entry: "kevwan", ok: true},
+		{name: "company", login: "alice", company: "@google", entry: "google", ok: true},
+		{name: "company name", login: "alice", company: "Google", entry: "google", ok: true},
+		{name: "org", login: "bob", orgs: []string{"Google"}, entry: "google", ok: true},
+		{name: "none", login: "carol", company: "googler fans"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			user := &github.User{
+				Login:   github.String(test.login),
+				Company: github.String(test.company),
+			}
+			entry, ok := matchEntries(watchlist, user, test.orgs)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.entry, entry)
+		})
+	}
+}
+
+func TestWatchPoll(t *testing.T) {
+	takeEvents()
+	var gazers string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/graphql":
+			var req graphqlRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "kubernetes", req.Variables["owner"])
+			assert.Contains(t, req.Query, "STARRED_AT, direction: DESC")
+			w.Write([]byte(`{"data": {"repository": {"stargazers": {"edges": [` + gazers + `]}}}}`))
+		case "/users/kevwan":
+			w.Write([]byte(`{"login": "kevwan", "html_url": "https://github.com/kevwan"}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer svr.Close()
+
+	var rs recordSender
+	m := NewWatchMonitor(Config{Watchlist: []string{"kevwan"}}, &rs)
+	m.cli.BaseURL, _ = url.Parse(svr.URL + "/")
+	gazers = `{"starredAt": "2024-01-01T00:00:00Z", "node": {"login": "alice", "databaseId": 1}}`
+	assert.NoError(t, m.poll("kubernetes/kubernetes", false))
+	gazers = `{"starredAt": "2024-01-02T00:00:00Z", "node": {"login": "kevwan", "databaseId": 2}},` + gazers
+	assert.NoError(t, m.poll("kubernetes/kubernetes", true))
+
+	assert.Empty(t, takeEvents())
+	if assert.Len(t, rs.messages, 1) {
+		assert.Equal(t, sender.VIPEvent, rs.messages[0].Event)
+		assert.Equal(t, "kubernetes/kubernetes", rs.messages[0].Repo)
+	}
+}
+
+type recordSender struct {
+	messages []sender.Message
+}
+
+func (s *recordSender) Send(message string) error {
+	return s.SendMessage(sender.Message{Text: message})
+}
+
+func (s *recordSender) SendMessage(msg sender.Message) error {
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func TestRequestLatestStargazersError(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"repository": null}, "errors": [{"message": "Could not resolve"}]}`))
+	}))
+	defer svr.Close()
+
+	cli := CreateClient("")
+	cli.BaseURL, _ = url.Parse(svr.URL + "/")
+	_, err := RequestLatestStargazers(cli, "zeromicro", "nothing", 10)
+	assert.ErrorContains(t, err, "Could not resolve")
+}
+
+func TestMatchWatchlistCachesOrgs(t *testing.T) {
+	var calls int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/users/watch-test-user/orgs", r.URL.Path)
+		w.Write([]byte(`[{"login": "google"}]`))
+	}))
+	defer svr.Close()
+
+	cli := CreateClient("")
+	cli.BaseURL, _ = url.Parse(svr.URL + "/")
+	user := &github.User{Login: github.String("watch-test-user")}
+	for i := 0; i < 2; i++ {
+		entry, ok := matchWatchlist(cli, []string{"google"}, user)
+		assert.True(t, ok)
+		assert.Equal(t, "google", entry)
+	}
+	assert.Equal(t, 1, calls)
+}
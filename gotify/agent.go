@@ -0,0 +1,150 @@
+package gotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"stargazers/sender"
+
+	"github.com/zeromicro/go-zero/rest/httpc"
+)
+
+const (
+	lowPriority     = 2
+	defaultPriority = 5
+	highPriority    = 8
+	markdownType    = "text/markdown"
+)
+
+type (
+	app struct {
+		c *Gotify
+	}
+
+	// https://gotify.net/api-docs#/message/createMessage
+	request struct {
+		Title    string  `json:"title,omitempty"`
+		Message  string  `json:"message"`
+		Priority int     `json:"priority"`
+		Extras   *extras `json:"extras,omitempty"`
+	}
+
+	// https://gotify.net/docs/msgextras
+	extras struct {
+		Display      *display      `json:"client::display,omitempty"`
+		Notification *notification `json:"client::notification,omitempty"`
+	}
+
+	display struct {
+		ContentType string `json:"contentType"`
+	}
+
+	notification struct {
+		Click       *click `json:"click,omitempty"`
+		BigImageUrl string `json:"bigImageUrl,omitempty"`
+	}
+
+	click struct {
+		Url string `json:"url"`
+	}
+)
+
+func NewSender(c *Gotify) sender.Sender {
+	return &app{c: c}
+}
+
+func (a *app) Send(message string) error {
+	return a.SendContext(context.Background(), message)
+}
+
+func (a *app) SendContext(ctx context.Context, message string) error {
+	return a.post(ctx, request{
+		Message:  message,
+		Priority: defaultPriority,
+	})
+}
+
+func (a *app) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *app) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	var lines []string
+	for _, field := range msg.Fields {
+		lines = append(lines, fmt.Sprintf("**%s**: %s  ", field.Name, field.Value))
+	}
+	if len(msg.Text) > 0 {
+		lines = append(lines, msg.Text)
+	}
+	for _, link := range msg.Links {
+		lines = append(lines, fmt.Sprintf("[%s](%s)  ", link.Title, link.URL))
+	}
+
+	ext := extras{
+		Display: &display{
+			ContentType: markdownType,
+		},
+	}
+	if url := msg.ClickUrl(); len(url) > 0 || len(msg.Avatar) > 0 {
+		ext.Notification = &notification{
+			BigImageUrl: msg.Avatar,
+		}
+		if len(url) > 0 {
+			ext.Notification.Click = &click{
+				Url: url,
+			}
+		}
+	}
+
+	return a.post(ctx, request{
+		Title:    msg.Title,
+		Message:  strings.Join(lines, "\n"),
+		Priority: priority(msg.Urgency()),
+		Extras:   &ext,
+	})
+}
+
+func (a *app) post(ctx context.Context, req request) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(a.c.ServerUrl, "/")+"/message", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Gotify-Key", a.c.AppToken)
+
+	resp, err := httpc.DoRequest(r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return sender.StatusError(resp.StatusCode,
+			fmt.Errorf("gotify: %s, %s", resp.Status, strings.TrimSpace(string(body))))
+	}
+
+	return nil
+}
+
+func priority(urgency sender.Urgency) int {
+	switch urgency {
+	case sender.HighUrgency:
+		return highPriority
+	case sender.LowUrgency:
+		return lowPriority
+	default:
+		return defaultPriority
+	}
+}
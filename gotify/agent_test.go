@@ -0,0 +1,68 @@
+package gotify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendMessage(t *testing.T) {
+	var req request
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/message", r.URL.Path)
+		assert.Equal(t, "app_token", r.Header.Get("X-Gotify-Key"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+	}))
+	defer svr.Close()
+
+	msg := sender.Message{
+		Event:  sender.StarEvent,
+		Title:  "new star",
+		Avatar: "https://avatars.githubusercontent.com/u/1",
+	}
+	msg.AddField("user", "kevwan")
+	msg.AddLink("profile", "https://github.com/kevwan")
+
+	s := NewSender(&Gotify{
+		ServerUrl: svr.URL + "/",
+		AppToken:  "app_token",
+	})
+	assert.NoError(t, sender.SendMessage(s, msg))
+	assert.Equal(t, request{
+		Title:    "new star",
+		Message:  "**user**: kevwan  \n[profile](https://github.com/kevwan)  ",
+		Priority: lowPriority,
+		Extras: &extras{
+			Display: &display{
+				ContentType: markdownType,
+			},
+			Notification: &notification{
+				Click: &click{
+					Url: "https://github.com/kevwan",
+				},
+				BigImageUrl: "https://avatars.githubusercontent.com/u/1",
+			},
+		},
+	}, req)
+}
+
+func TestSendRejected(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer svr.Close()
+
+	var pe *sender.PermanentError
+	assert.ErrorAs(t, NewSender(&Gotify{ServerUrl: svr.URL}).Send("hello"), &pe)
+}
+
+func TestPriority(t *testing.T) {
+	assert.Equal(t, highPriority, priority(sender.HighUrgency))
+	assert.Equal(t, lowPriority, priority(sender.LowUrgency))
+	assert.Equal(t, defaultPriority, priority(sender.DefaultUrgency))
+}
@@ -0,0 +1,7 @@
+package gotify
+
+type Gotify struct {
+	ServerUrl string `json:"serverUrl"`
+	// AppToken is the token of the application to push the messages.
+	AppToken string `json:"appToken"`
+}
@@ -1,37 +1,57 @@
 package lark
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"stargazers/sender"
 
 	"github.com/zeromicro/go-zero/rest/httpc"
-
-	"github.com/fastwego/feishu"
-	"github.com/fastwego/feishu/apis/message"
 )
 
-const messageType = "text"
+const (
+	messageType     = "text"
+	larkDomain      = "lark"
+	feishuBaseUrl   = "https://open.feishu.cn"
+	larkBaseUrl     = "https://open.larksuite.com"
+	tokenPath       = "/open-apis/auth/v3/tenant_access_token/internal"
+	sendMessagePath = "/open-apis/message/v4/send/"
+	// the request body of the custom bots is limited to 20KB
+	maxTextSize = 18 << 10
+)
 
 type (
-	app struct {
-		app           *feishu.App
-		receiver      string
-		receiverEmail string
+	accessToken struct {
+		Token  string
+		Expire time.Time
 	}
 
-	larkMessage struct {
-		UserId  string  `json:"user_id"`
-		Email   string  `json:"email"`
-		MsgType string  `json:"msg_type"`
-		Content content `json:"content"`
+	app struct {
+		c       *Lark
+		baseUrl string
+		lock    sync.Mutex
+		token   accessToken
+		parts   sender.Parts
 	}
 
-	content struct {
-		Text string `json:"text"`
+	larkMessage struct {
+		ChatId  string    `json:"chat_id,omitempty"`
+		UserId  string    `json:"user_id,omitempty"`
+		Email   string    `json:"email,omitempty"`
+		MsgType string    `json:"msg_type"`
+		Content *textBody `json:"content,omitempty"`
+		Card    *card     `json:"card,omitempty"`
 	}
 
 	textBody struct {
@@ -39,17 +59,37 @@ type (
 	}
 
 	request struct {
-		MsgType string   `json:"msg_type"`
-		Content textBody `json:"content"`
+		Timestamp string    `json:"timestamp,omitempty"`
+		Sign      string    `json:"sign,omitempty"`
+		MsgType   string    `json:"msg_type"`
+		Content   *textBody `json:"content,omitempty"`
+		Card      *card     `json:"card,omitempty"`
 	}
 
+	// response covers both the legacy and the current formats.
 	response struct {
 		StatusCode    int    `json:"StatusCode"`
 		StatusMessage string `json:"StatusMessage"`
+		Code          int    `json:"code"`
+		Msg           string `json:"msg"`
+	}
+
+	tokenRequest struct {
+		AppId     string `json:"app_id"`
+		AppSecret string `json:"app_secret"`
+	}
+
+	tokenResponse struct {
+		Code   int    `json:"code"`
+		Msg    string `json:"msg"`
+		Token  string `json:"tenant_access_token"`
+		Expire int    `json:"expire"`
 	}
 
 	webhookApp struct {
-		url string
+		url    string
+		secret string
+		parts  sender.Parts
 	}
 )
 
@@ -62,60 +102,184 @@ func NewSender(c *Lark) sender.Sender {
 }
 
 func newApp(c *Lark) *app {
+	baseUrl := c.BaseUrl
+	if len(baseUrl) == 0 {
+		baseUrl = feishuBaseUrl
+		if c.Domain == larkDomain {
+			baseUrl = larkBaseUrl
+		}
+	}
+
 	return &app{
-		app: feishu.NewApp(feishu.AppConfig{
-			AppId:     c.AppId,
-			AppSecret: c.AppSecret,
-		}),
-		receiver:      c.Receiver,
-		receiverEmail: c.ReceiverEmail,
+		c:       c,
+		baseUrl: strings.TrimSuffix(baseUrl, "/"),
 	}
 }
 
 func (a *app) Send(text string) error {
-	payload, err := json.Marshal(larkMessage{
-		UserId:  a.receiver,
-		Email:   a.receiverEmail,
-		MsgType: messageType,
-		Content: content{
-			Text: text,
-		},
+	return a.SendContext(context.Background(), text)
+}
+
+func (a *app) SendContext(ctx context.Context, text string) error {
+	parts := sender.Split(text, maxTextSize, sender.Bytes)
+	return a.parts.Send(text, parts, func(part string) error {
+		return a.send(ctx, larkMessage{
+			UserId:  a.c.Receiver,
+			Email:   a.c.ReceiverEmail,
+			MsgType: messageType,
+			Content: &textBody{
+				Text: part,
+			},
+		})
+	})
+}
+
+func (a *app) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *app) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	c := renderCard(msg)
+	return a.send(ctx, larkMessage{
+		UserId:  a.c.Receiver,
+		Email:   a.c.ReceiverEmail,
+		MsgType: cardMessageType,
+		Card:    &c,
 	})
+}
+
+func (a *app) send(ctx context.Context, msg larkMessage) error {
+	token, err := a.getToken(ctx)
 	if err != nil {
 		return err
 	}
 
-	_, err = message.Send(a.app, payload)
-	return err
+	var rsp response
+	if err := postJSON(ctx, a.baseUrl+sendMessagePath, "Bearer "+token, msg, &rsp); err != nil {
+		return err
+	}
+
+	return rsp.err()
+}
+
+func (a *app) getToken(ctx context.Context) (string, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if time.Until(a.token.Expire) > time.Minute {
+		return a.token.Token, nil
+	}
+
+	var rsp tokenResponse
+	if err := postJSON(ctx, a.baseUrl+tokenPath, "", tokenRequest{
+		AppId:     a.c.AppId,
+		AppSecret: a.c.AppSecret,
+	}, &rsp); err != nil {
+		return "", err
+	}
+	if rsp.Code != 0 {
+		return "", fmt.Errorf("lark: %d %s", rsp.Code, rsp.Msg)
+	}
+
+	a.token = accessToken{
+		Token:  rsp.Token,
+		Expire: time.Now().Add(time.Duration(rsp.Expire) * time.Second),
+	}
+
+	return a.token.Token, nil
 }
 
 func newWebhook(c *Lark) sender.Sender {
 	return &webhookApp{
-		url: c.WebhookUrl,
+		url:    c.WebhookUrl,
+		secret: c.Secret,
 	}
 }
 
 func (a *webhookApp) Send(message string) error {
-	req := request{
-		MsgType: messageType,
-		Content: textBody{
-			Text: message,
-		},
+	return a.SendContext(context.Background(), message)
+}
+
+func (a *webhookApp) SendContext(ctx context.Context, message string) error {
+	parts := sender.Split(message, maxTextSize, sender.Bytes)
+	return a.parts.Send(message, parts, func(part string) error {
+		return a.post(ctx, request{
+			MsgType: messageType,
+			Content: &textBody{
+				Text: part,
+			},
+		})
+	})
+}
+
+func (a *webhookApp) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *webhookApp) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	c := renderCard(msg)
+	return a.post(ctx, request{
+		MsgType: cardMessageType,
+		Card:    &c,
+	})
+}
+
+func (a *webhookApp) post(ctx context.Context, req request) error {
+	if len(a.secret) > 0 {
+		req.Timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+		req.Sign = sign(req.Timestamp, a.secret)
+	}
+
+	var rsp response
+	if err := postJSON(ctx, a.url, "", req, &rsp); err != nil {
+		return err
+	}
+
+	return rsp.err()
+}
+
+func (r response) err() error {
+	switch {
+	case r.StatusCode != 0:
+		return errors.New(r.StatusMessage)
+	case r.Code != 0:
+		return fmt.Errorf("lark: %d %s", r.Code, r.Msg)
+	default:
+		return nil
 	}
+}
 
-	resp, err := httpc.Do(context.Background(), http.MethodPost, a.url, req)
+func postJSON(ctx context.Context, url, authorization string, body, result any) error {
+	payload, err := json.Marshal(body)
 	if err != nil {
 		return err
 	}
 
-	var rsp response
-	if err := httpc.Parse(resp, &rsp); err != nil {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if len(authorization) > 0 {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := httpc.DoRequest(req)
+	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
-	if rsp.StatusCode != 0 {
-		return errors.New(rsp.StatusMessage)
+	if resp.StatusCode != http.StatusOK {
+		return sender.StatusError(resp.StatusCode, fmt.Errorf("lark: %s", resp.Status))
 	}
 
-	return nil
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// sign signs the timestamp with the secret of the custom bot,
+// the key is the string to sign and the data is empty as Lark requires.
+func sign(timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
 }
@@ -0,0 +1,39 @@
+package lark
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, "fiWS2+gh28DOydAv7hzONH/mDn9+b1Y4Y5ivXWXy8vA=", sign("1700000000", "secret"))
+}
+
+func TestNewApp(t *testing.T) {
+	assert.Equal(t, feishuBaseUrl, newApp(&Lark{Domain: "feishu"}).baseUrl)
+	assert.Equal(t, larkBaseUrl, newApp(&Lark{Domain: "lark"}).baseUrl)
+	assert.Equal(t, "https://open.example.com", newApp(&Lark{
+		Domain:  "lark",
+		BaseUrl: "https://open.example.com/",
+	}).baseUrl)
+}
+
+func TestPostJSONRejected(t *testing.T) {
+	var status int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer svr.Close()
+
+	var pe *sender.PermanentError
+	status = http.StatusBadRequest
+	assert.ErrorAs(t, postJSON(context.Background(), svr.URL, "", struct{}{}, &struct{}{}), &pe)
+	status = http.StatusTooManyRequests
+	assert.NotErrorAs(t, postJSON(context.Background(), svr.URL, "", struct{}{}, &struct{}{}), &pe)
+}
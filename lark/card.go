@@ -0,0 +1,134 @@
+package lark
+
+import (
+	"fmt"
+	"strings"
+
+	"stargazers/sender"
+)
+
+const (
+	cardMessageType = "interactive"
+	// cards are limited to 30KB, leave the room for the other elements
+	maxCardTextSize = 20 << 10
+)
+
+var mdEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "*", "&#42;", "~", "&sim;")
+
+type (
+	card struct {
+		Config   cardConfig    `json:"config"`
+		Header   cardHeader    `json:"header"`
+		Elements []cardElement `json:"elements"`
+	}
+
+	cardConfig struct {
+		WideScreenMode bool `json:"wide_screen_mode"`
+	}
+
+	cardHeader struct {
+		Title    cardText `json:"title"`
+		Template string   `json:"template"`
+	}
+
+	cardElement struct {
+		Tag     string       `json:"tag"`
+		Text    *cardText    `json:"text,omitempty"`
+		Fields  []cardField  `json:"fields,omitempty"`
+		Actions []cardButton `json:"actions,omitempty"`
+	}
+
+	cardField struct {
+		IsShort bool     `json:"is_short"`
+		Text    cardText `json:"text"`
+	}
+
+	cardButton struct {
+		Tag  string   `json:"tag"`
+		Text cardText `json:"text"`
+		Url  string   `json:"url"`
+		Type string   `json:"type"`
+	}
+
+	cardText struct {
+		Tag     string `json:"tag"`
+		Content string `json:"content"`
+	}
+)
+
+// renderCard renders msg as an interactive card. The avatar is left out,
+// because card images must be uploaded to Lark first.
+func renderCard(msg sender.Message) card {
+	title := msg.Title
+	if len(title) == 0 {
+		title = "stargazers"
+	}
+
+	c := card{
+		Config: cardConfig{
+			WideScreenMode: true,
+		},
+		Header: cardHeader{
+			Title: cardText{
+				Tag:     "plain_text",
+				Content: title,
+			},
+			Template: headerTemplate(msg.Color),
+		},
+	}
+
+	if len(msg.Fields) > 0 {
+		fields := cardElement{
+			Tag: "div",
+		}
+		for _, field := range msg.Fields {
+			fields.Fields = append(fields.Fields, cardField{
+				IsShort: true,
+				Text: cardText{
+					Tag:     "lark_md",
+					Content: fmt.Sprintf("**%s**\n%s", mdEscaper.Replace(field.Name), mdEscaper.Replace(field.Value)),
+				},
+			})
+		}
+		c.Elements = append(c.Elements, fields)
+	}
+
+	if len(msg.Text) > 0 {
+		c.Elements = append(c.Elements, cardElement{
+			Tag: "div",
+			Text: &cardText{
+				Tag:     "plain_text",
+				Content: sender.Truncate(msg.Text, maxCardTextSize, sender.Bytes),
+			},
+		})
+	}
+
+	if len(msg.Links) > 0 {
+		actions := cardElement{
+			Tag: "action",
+		}
+		for _, link := range msg.Links {
+			actions.Actions = append(actions.Actions, cardButton{
+				Tag: "button",
+				Text: cardText{
+					Tag:     "plain_text",
+					Content: link.Title,
+				},
+				Url:  link.URL,
+				Type: "default",
+			})
+		}
+		c.Elements = append(c.Elements, actions)
+	}
+
+	return c
+}
+
+func headerTemplate(color sender.Color) string {
+	switch color {
+	case sender.ColorGreen, sender.ColorRed, sender.ColorOrange, sender.ColorGrey:
+		return string(color)
+	default:
+		return string(sender.ColorBlue)
+	}
+}
@@ -0,0 +1,44 @@
+package lark
+
+import (
+	"testing"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderCard(t *testing.T) {
+	msg := sender.Message{
+		Title: "new star",
+		Color: sender.ColorGreen,
+		Text:  "hello",
+	}
+	msg.AddField("user", "*kevwan*")
+	msg.AddLink("profile", "https://github.com/kevwan")
+
+	c := renderCard(msg)
+	assert.Equal(t, cardText{Tag: "plain_text", Content: "new star"}, c.Header.Title)
+	assert.Equal(t, "green", c.Header.Template)
+	if assert.Len(t, c.Elements, 3) {
+		assert.Equal(t, []cardField{
+			{
+				IsShort: true,
+				Text: cardText{
+					Tag:     "lark_md",
+					Content: "**user**\n&#42;kevwan&#42;",
+				},
+			},
+		}, c.Elements[0].Fields)
+		assert.Equal(t, &cardText{Tag: "plain_text", Content: "hello"}, c.Elements[1].Text)
+		assert.Equal(t, "action", c.Elements[2].Tag)
+		assert.Equal(t, "https://github.com/kevwan", c.Elements[2].Actions[0].Url)
+	}
+}
+
+func TestRenderCardDefaults(t *testing.T) {
+	c := renderCard(sender.Message{Color: sender.ColorBlue})
+	assert.Equal(t, "stargazers", c.Header.Title.Content)
+	assert.Equal(t, "blue", c.Header.Template)
+	assert.Empty(t, c.Elements)
+}
@@ -6,4 +6,10 @@ type Lark struct {
 	Receiver      string `json:"receiver,optional"`
 	ReceiverEmail string `json:"receiver_email,optional=!receiver"`
 	WebhookUrl    string `json:"webhook_url,optional"`
+	// Secret is the signing secret of the custom bot on WebhookUrl.
+	Secret string `json:"secret,optional"`
+	// Domain is feishu for Feishu in China, or lark for Lark Suite.
+	Domain string `json:"domain,default=feishu,options=feishu|lark"`
+	// BaseUrl overrides the open API endpoint of Domain.
+	BaseUrl string `json:"baseUrl,optional"`
 }
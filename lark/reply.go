@@ -0,0 +1,24 @@
+package lark
+
+import "context"
+
+// Replier replies to the chats with the app credentials, used by the bot commands.
+type Replier struct {
+	app *app
+}
+
+func NewReplier(c *Lark) *Replier {
+	return &Replier{
+		app: newApp(c),
+	}
+}
+
+func (r *Replier) Reply(ctx context.Context, chatId, text string) error {
+	return r.app.send(ctx, larkMessage{
+		ChatId:  chatId,
+		MsgType: messageType,
+		Content: &textBody{
+			Text: text,
+		},
+	})
+}
@@ -0,0 +1,171 @@
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/zeromicro/go-zero/rest/httpc"
+)
+
+const (
+	htmlFormat       = "org.matrix.custom.html"
+	limitExceeded    = "M_LIMIT_EXCEEDED"
+	defaultRetryWait = time.Second * 5
+)
+
+type (
+	app struct {
+		c   *Matrix
+		txn atomic.Int64
+		// failed is the last failed event, whose transaction id is reused on retries.
+		lock   sync.Mutex
+		failed failedEvent
+	}
+
+	failedEvent struct {
+		payload []byte
+		txnId   string
+	}
+
+	// https://spec.matrix.org/latest/client-server-api/#mroommessage
+	request struct {
+		MsgType       string `json:"msgtype"`
+		Body          string `json:"body"`
+		Format        string `json:"format,omitempty"`
+		FormattedBody string `json:"formatted_body,omitempty"`
+	}
+
+	// https://spec.matrix.org/latest/client-server-api/#rate-limiting
+	errorResponse struct {
+		ErrCode      string `json:"errcode"`
+		Error        string `json:"error"`
+		RetryAfterMs int64  `json:"retry_after_ms"`
+	}
+)
+
+func NewSender(c *Matrix) sender.Sender {
+	return &app{c: c}
+}
+
+func (a *app) Send(message string) error {
+	return a.SendContext(context.Background(), message)
+}
+
+func (a *app) SendContext(ctx context.Context, message string) error {
+	return a.send(ctx, request{
+		MsgType: a.c.MsgType,
+		Body:    message,
+	})
+}
+
+func (a *app) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *app) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	return a.send(ctx, request{
+		MsgType:       a.c.MsgType,
+		Body:          msg.String(),
+		Format:        htmlFormat,
+		FormattedBody: render(msg),
+	})
+}
+
+// send puts the event into the room. A retry of the failed event reuses its transaction id,
+// so that the homeserver doesn't post the message twice.
+func (a *app) send(ctx context.Context, req request) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	txnId := a.txnId(payload)
+	target := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimSuffix(a.c.HomeserverUrl, "/"), url.PathEscape(a.c.RoomId), txnId)
+	err = a.put(ctx, target, payload)
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if err != nil {
+		a.failed = failedEvent{
+			payload: payload,
+			txnId:   txnId,
+		}
+	} else {
+		a.failed = failedEvent{}
+	}
+
+	return err
+}
+
+func (a *app) put(ctx context.Context, target string, payload []byte) error {
+	r, err := http.NewRequestWithContext(ctx, http.MethodPut, target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Authorization", "Bearer "+a.c.AccessToken)
+
+	resp, err := httpc.DoRequest(r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var rsp errorResponse
+	if err := json.Unmarshal(body, &rsp); err != nil {
+		return sender.StatusError(resp.StatusCode, fmt.Errorf("matrix: %s", resp.Status))
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && rsp.ErrCode != limitExceeded {
+		return sender.StatusError(resp.StatusCode, fmt.Errorf("matrix: %s %s", rsp.ErrCode, rsp.Error))
+	}
+
+	return &sender.RetryAfterError{
+		Err:   fmt.Errorf("matrix: %s", rsp.Error),
+		After: retryAfter(resp.Header.Get("Retry-After"), rsp.RetryAfterMs),
+	}
+}
+
+func (a *app) txnId(payload []byte) string {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if bytes.Equal(a.failed.payload, payload) {
+		return a.failed.txnId
+	}
+
+	return fmt.Sprintf("stargazers.%d.%d", time.Now().UnixNano(), a.txn.Add(1))
+}
+
+// retryAfter prefers retry_after_ms in the body, which is deprecated but more precise.
+func retryAfter(header string, ms int64) time.Duration {
+	if ms > 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+	if secs, err := strconv.Atoi(header); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+
+	return defaultRetryWait
+}
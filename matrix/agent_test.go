@@ -0,0 +1,74 @@
+package matrix
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	msg := sender.Message{
+		Title: "new star",
+		Color: sender.ColorGreen,
+		Text:  "a <b>\nc",
+	}
+	msg.AddField("user", "kevwan")
+	msg.AddLink("profile", "https://github.com/kevwan")
+
+	assert.Equal(t, `<strong><font color="#2eb67d">new star</font></strong><br>`+
+		`<strong>user</strong>: kevwan<br>a &lt;b&gt;<br>c<br>`+
+		`<a href="https://github.com/kevwan">profile</a>`, render(msg))
+}
+
+func TestRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Millisecond*1500, retryAfter("3", 1500))
+	assert.Equal(t, time.Second*3, retryAfter("3", 0))
+	assert.Equal(t, defaultRetryWait, retryAfter("", 0))
+}
+
+func TestSendLimitExceeded(t *testing.T) {
+	var paths []string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if len(paths) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(errorResponse{
+				ErrCode:      limitExceeded,
+				Error:        "Too many requests",
+				RetryAfterMs: 10,
+			})
+			return
+		}
+
+		var req request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "m.notice", req.MsgType)
+		assert.Equal(t, "hello", req.Body)
+		w.Write([]byte(`{"event_id":"$1"}`))
+	}))
+	defer svr.Close()
+
+	s := NewSender(&Matrix{
+		HomeserverUrl: svr.URL,
+		AccessToken:   "token",
+		RoomId:        "!room:matrix.org",
+		MsgType:       "m.notice",
+	})
+	var rae *sender.RetryAfterError
+	if assert.ErrorAs(t, s.Send("hello"), &rae) {
+		assert.Equal(t, time.Millisecond*10, rae.After)
+	}
+	assert.NoError(t, s.Send("hello"))
+	if assert.Len(t, paths, 2) {
+		assert.Equal(t, paths[0], paths[1])
+		assert.Contains(t, paths[0], "/rooms/%21room:matrix.org/send/m.room.message/")
+	}
+}
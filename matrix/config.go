@@ -0,0 +1,10 @@
+package matrix
+
+type Matrix struct {
+	// HomeserverUrl is the client API url of the homeserver.
+	HomeserverUrl string `json:"homeserverUrl,default=https://matrix.org"`
+	AccessToken   string `json:"accessToken"`
+	// RoomId is like !abcdefg:matrix.org, the user of AccessToken needs to join the room.
+	RoomId  string `json:"roomId"`
+	MsgType string `json:"msgType,default=m.notice,options=m.notice|m.text"`
+}
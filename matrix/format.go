@@ -0,0 +1,31 @@
+package matrix
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"stargazers/sender"
+)
+
+// render renders msg in the html subset that Matrix clients support.
+func render(msg sender.Message) string {
+	var lines []string
+	if len(msg.Title) > 0 {
+		lines = append(lines, fmt.Sprintf(`<strong><font color="%s">%s</font></strong>`,
+			msg.Color.Hex(), html.EscapeString(msg.Title)))
+	}
+	for _, field := range msg.Fields {
+		lines = append(lines, fmt.Sprintf("<strong>%s</strong>: %s",
+			html.EscapeString(field.Name), html.EscapeString(field.Value)))
+	}
+	if len(msg.Text) > 0 {
+		lines = append(lines, strings.ReplaceAll(html.EscapeString(msg.Text), "\n", "<br>"))
+	}
+	for _, link := range msg.Links {
+		lines = append(lines, fmt.Sprintf(`<a href="%s">%s</a>`,
+			html.EscapeString(link.URL), html.EscapeString(link.Title)))
+	}
+
+	return strings.Join(lines, "<br>")
+}
@@ -0,0 +1,94 @@
+package mattermost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"stargazers/sender"
+
+	"github.com/zeromicro/go-zero/rest/httpc"
+)
+
+// https://docs.mattermost.com/configure/environment-configuration-settings.html, 16383 characters per post
+const maxTextLength = 16383
+
+type (
+	app struct {
+		c     *Mattermost
+		parts sender.Parts
+	}
+
+	request struct {
+		Text        string              `json:"text,omitempty"`
+		Channel     string              `json:"channel,omitempty"`
+		Username    string              `json:"username,omitempty"`
+		IconUrl     string              `json:"icon_url,omitempty"`
+		Attachments []sender.Attachment `json:"attachments,omitempty"`
+	}
+)
+
+func NewSender(c *Mattermost) sender.Sender {
+	return &app{c: c}
+}
+
+func (a *app) Send(message string) error {
+	return a.SendContext(context.Background(), message)
+}
+
+func (a *app) SendContext(ctx context.Context, message string) error {
+	parts := sender.Split(message, maxTextLength, sender.Runes)
+	return a.parts.Send(message, parts, func(part string) error {
+		return a.post(ctx, request{
+			Text:     part,
+			Channel:  a.c.Channel,
+			Username: a.c.Username,
+			IconUrl:  a.c.IconUrl,
+		})
+	})
+}
+
+func (a *app) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *app) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	return a.post(ctx, request{
+		Channel:     a.c.Channel,
+		Username:    a.c.Username,
+		IconUrl:     a.c.IconUrl,
+		Attachments: []sender.Attachment{sender.NewAttachment(msg, maxTextLength)},
+	})
+}
+
+func (a *app) post(ctx context.Context, req request) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, a.c.WebhookUrl,
+		bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpc.DoRequest(r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return sender.StatusError(resp.StatusCode,
+			fmt.Errorf("mattermost: %s, %s", resp.Status, strings.TrimSpace(string(body))))
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package mattermost
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendMessage(t *testing.T) {
+	var req request
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+	}))
+	defer svr.Close()
+
+	msg := sender.Message{
+		Title:  "new star",
+		Color:  sender.ColorGreen,
+		Avatar: "https://avatars.githubusercontent.com/u/1",
+	}
+	msg.AddField("user", "kevwan")
+	msg.AddLink("profile", "https://github.com/kevwan")
+
+	s := NewSender(&Mattermost{
+		WebhookUrl: svr.URL,
+		Channel:    "town-square",
+		Username:   "stargazers",
+	})
+	assert.NoError(t, sender.SendMessage(s, msg))
+	assert.Equal(t, request{
+		Channel:  "town-square",
+		Username: "stargazers",
+		Attachments: []sender.Attachment{
+			{
+				Fallback:  "new star\nuser: kevwan\nprofile: https://github.com/kevwan",
+				Color:     "#2eb67d",
+				Title:     "new star",
+				TitleLink: "https://github.com/kevwan",
+				Text:      "[profile](https://github.com/kevwan)",
+				Fields: []sender.AttachmentField{
+					{Title: "user", Value: "kevwan", Short: true},
+				},
+				ThumbUrl: "https://avatars.githubusercontent.com/u/1",
+			},
+		},
+	}, req)
+}
@@ -0,0 +1,9 @@
+package mattermost
+
+type Mattermost struct {
+	WebhookUrl string `json:"webhookUrl"`
+	// Channel overrides the default channel of the webhook, if the webhook allows.
+	Channel  string `json:"channel,optional"`
+	Username string `json:"username,optional"`
+	IconUrl  string `json:"iconUrl,optional"`
+}
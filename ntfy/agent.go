@@ -0,0 +1,125 @@
+package ntfy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"stargazers/sender"
+
+	"github.com/zeromicro/go-zero/rest/httpc"
+)
+
+// https://docs.ntfy.sh/publish/#message-priority
+const (
+	lowPriority     = 2
+	defaultPriority = 3
+	highPriority    = 4
+)
+
+type (
+	app struct {
+		c *Ntfy
+	}
+
+	// https://docs.ntfy.sh/publish/#publish-as-json
+	request struct {
+		Topic    string   `json:"topic"`
+		Message  string   `json:"message"`
+		Title    string   `json:"title,omitempty"`
+		Priority int      `json:"priority,omitempty"`
+		Tags     []string `json:"tags,omitempty"`
+		Click    string   `json:"click,omitempty"`
+		Icon     string   `json:"icon,omitempty"`
+		Markdown bool     `json:"markdown,omitempty"`
+	}
+)
+
+func NewSender(c *Ntfy) sender.Sender {
+	return &app{c: c}
+}
+
+func (a *app) Send(message string) error {
+	return a.SendContext(context.Background(), message)
+}
+
+func (a *app) SendContext(ctx context.Context, message string) error {
+	return a.post(ctx, request{
+		Topic:   a.c.Topic,
+		Message: message,
+	})
+}
+
+func (a *app) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *app) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	var lines []string
+	for _, field := range msg.Fields {
+		lines = append(lines, fmt.Sprintf("%s: %s", field.Name, field.Value))
+	}
+	if len(msg.Text) > 0 {
+		lines = append(lines, msg.Text)
+	}
+
+	req := request{
+		Topic:    a.c.Topic,
+		Message:  strings.Join(lines, "\n"),
+		Title:    msg.Title,
+		Priority: priority(msg.Urgency()),
+		Click:    msg.ClickUrl(),
+		Icon:     msg.Avatar,
+	}
+	if len(msg.Event) > 0 {
+		req.Tags = []string{msg.Event}
+	}
+
+	return a.post(ctx, req)
+}
+
+func (a *app) post(ctx context.Context, req request) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(a.c.ServerUrl, "/"), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	if len(a.c.Token) > 0 {
+		r.Header.Set("Authorization", "Bearer "+a.c.Token)
+	}
+
+	resp, err := httpc.DoRequest(r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return sender.StatusError(resp.StatusCode,
+			fmt.Errorf("ntfy: %s, %s", resp.Status, strings.TrimSpace(string(body))))
+	}
+
+	return nil
+}
+
+func priority(urgency sender.Urgency) int {
+	switch urgency {
+	case sender.HighUrgency:
+		return highPriority
+	case sender.LowUrgency:
+		return lowPriority
+	default:
+		return defaultPriority
+	}
+}
@@ -0,0 +1,52 @@
+package ntfy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendMessage(t *testing.T) {
+	var req request
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer tk_token", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+	}))
+	defer svr.Close()
+
+	msg := sender.Message{
+		Event:  sender.StarEvent,
+		Title:  "new star",
+		Avatar: "https://avatars.githubusercontent.com/u/1",
+	}
+	msg.AddField("user", "kevwan")
+	msg.AddLink("repo", "https://github.com/zeromicro/go-zero")
+	msg.AddLink("profile", "https://github.com/kevwan")
+
+	s := NewSender(&Ntfy{
+		ServerUrl: svr.URL,
+		Topic:     "stars",
+		Token:     "tk_token",
+	})
+	assert.NoError(t, sender.SendMessage(s, msg))
+	assert.Equal(t, request{
+		Topic:    "stars",
+		Message:  "user: kevwan",
+		Title:    "new star",
+		Priority: lowPriority,
+		Tags:     []string{"star"},
+		Click:    "https://github.com/kevwan",
+		Icon:     "https://avatars.githubusercontent.com/u/1",
+	}, req)
+}
+
+func TestPriority(t *testing.T) {
+	assert.Equal(t, highPriority, priority(sender.HighUrgency))
+	assert.Equal(t, lowPriority, priority(sender.LowUrgency))
+	assert.Equal(t, defaultPriority, priority(sender.DefaultUrgency))
+}
@@ -0,0 +1,8 @@
+package ntfy
+
+type Ntfy struct {
+	ServerUrl string `json:"serverUrl,default=https://ntfy.sh"`
+	Topic     string `json:"topic"`
+	// Token is the access token, for the protected topics.
+	Token string `json:"token,optional"`
+}
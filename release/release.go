@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"stargazers/gh"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const publishedAtFormat = "2006-01-02 15:04"
+
+var (
+	repo   = flag.String("repo", "", "the github repo")
+	token  = flag.String("token", "", "the github token")
+	window = flag.Duration("window", time.Hour*72, "the duration before and after each release")
+)
+
+func main() {
+	flag.Parse()
+
+	if len(*token) == 0 {
+		flag.Usage()
+		return
+	}
+
+	cli := gh.CreateClient(*token)
+	owner, project, err := gh.ParseRepo(*repo)
+	logx.Must(err)
+	stargazers, err := gh.RequestAll(cli, owner, project)
+	logx.Must(err)
+	releases, err := gh.RequestReleases(cli, owner, project, nil, nil)
+	logx.Must(err)
+
+	stars := make([]time.Time, 0, len(stargazers))
+	for _, starAt := range stargazers {
+		stars = append(stars, starAt)
+	}
+
+	hours := int(window.Hours())
+	fmt.Printf("\n")
+	for _, velocity := range gh.StarVelocity(stars, releases, *window) {
+		fmt.Printf("release: %s, publishedAt: %s, %dh before: %d, %dh after: %d, change: %+d\n",
+			velocity.Tag, velocity.PublishedAt.Local().Format(publishedAtFormat),
+			hours, velocity.Before, hours, velocity.After, velocity.After-velocity.Before)
+	}
+}
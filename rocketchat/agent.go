@@ -0,0 +1,116 @@
+package rocketchat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"stargazers/sender"
+
+	"github.com/zeromicro/go-zero/rest/httpc"
+)
+
+// the default of Message_MaxAllowedSize, in characters
+const maxTextLength = 5000
+
+type (
+	app struct {
+		c     *RocketChat
+		parts sender.Parts
+	}
+
+	request struct {
+		Text        string              `json:"text,omitempty"`
+		Channel     string              `json:"channel,omitempty"`
+		Alias       string              `json:"alias,omitempty"`
+		Avatar      string              `json:"avatar,omitempty"`
+		Attachments []sender.Attachment `json:"attachments,omitempty"`
+	}
+
+	response struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error"`
+	}
+)
+
+func NewSender(c *RocketChat) sender.Sender {
+	return &app{c: c}
+}
+
+func (a *app) Send(message string) error {
+	return a.SendContext(context.Background(), message)
+}
+
+func (a *app) SendContext(ctx context.Context, message string) error {
+	parts := sender.Split(message, maxTextLength, sender.Runes)
+	return a.parts.Send(message, parts, func(part string) error {
+		return a.post(ctx, request{
+			Text:    part,
+			Channel: a.c.Channel,
+			Alias:   a.c.Username,
+			Avatar:  a.c.IconUrl,
+		})
+	})
+}
+
+func (a *app) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+// SendMessageContext sends the title as the text of the message, which the notifications show,
+// so the attachment goes without the title.
+func (a *app) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	att := sender.NewAttachment(msg, maxTextLength)
+	att.Title = ""
+	return a.post(ctx, request{
+		Text:        msg.Title,
+		Channel:     a.c.Channel,
+		Alias:       a.c.Username,
+		Avatar:      a.c.IconUrl,
+		Attachments: []sender.Attachment{att},
+	})
+}
+
+func (a *app) post(ctx context.Context, req request) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, a.c.WebhookUrl,
+		bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpc.DoRequest(r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return err
+	}
+
+	var rsp response
+	if err := json.Unmarshal(body, &rsp); err != nil {
+		return sender.StatusError(resp.StatusCode,
+			fmt.Errorf("rocketchat: %s, %s", resp.Status, strings.TrimSpace(string(body))))
+	}
+	if !rsp.Success {
+		if len(rsp.Error) == 0 {
+			return sender.StatusError(resp.StatusCode, errors.New("rocketchat: "+resp.Status))
+		}
+		return sender.StatusError(resp.StatusCode, errors.New("rocketchat: "+rsp.Error))
+	}
+
+	return nil
+}
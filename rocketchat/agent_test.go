@@ -0,0 +1,65 @@
+package rocketchat
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendMessage(t *testing.T) {
+	var req request
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Write([]byte(`{"success": true}`))
+	}))
+	defer svr.Close()
+
+	msg := sender.Message{
+		Title: "new star",
+		Color: sender.ColorGreen,
+		Text:  "top referrers",
+	}
+	msg.AddField("user", "kevwan")
+	msg.AddLink("profile", "https://github.com/kevwan")
+
+	s := NewSender(&RocketChat{
+		WebhookUrl: svr.URL,
+		Channel:    "#general",
+		Username:   "stargazers",
+	})
+	assert.NoError(t, sender.SendMessage(s, msg))
+	assert.Equal(t, request{
+		Text:    "new star",
+		Channel: "#general",
+		Alias:   "stargazers",
+		Attachments: []sender.Attachment{
+			{
+				Fallback:  "new star\nuser: kevwan\ntop referrers\nprofile: https://github.com/kevwan",
+				Color:     "#2eb67d",
+				TitleLink: "https://github.com/kevwan",
+				Text:      "top referrers\n[profile](https://github.com/kevwan)",
+				Fields: []sender.AttachmentField{
+					{Title: "user", Value: "kevwan", Short: true},
+				},
+			},
+		},
+	}, req)
+}
+
+func TestSendFailed(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"success": false, "error": "invalid channel"}`))
+	}))
+	defer svr.Close()
+
+	err := NewSender(&RocketChat{WebhookUrl: svr.URL}).Send("hello")
+	assert.EqualError(t, err, "rocketchat: invalid channel")
+	var pe *sender.PermanentError
+	assert.ErrorAs(t, err, &pe)
+}
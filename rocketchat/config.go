@@ -0,0 +1,9 @@
+package rocketchat
+
+type RocketChat struct {
+	WebhookUrl string `json:"webhookUrl"`
+	// Channel overrides the channel of the webhook, like #general or @user.
+	Channel  string `json:"channel,optional"`
+	Username string `json:"username,optional"`
+	IconUrl  string `json:"iconUrl,optional"`
+}
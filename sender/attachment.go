@@ -0,0 +1,58 @@
+package sender
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// Attachment is the Slack compatible message attachment of Mattermost and Rocket.Chat.
+	// https://developers.mattermost.com/integrate/reference/message-attachments/
+	Attachment struct {
+		Fallback  string            `json:"fallback,omitempty"`
+		Color     string            `json:"color,omitempty"`
+		Title     string            `json:"title,omitempty"`
+		TitleLink string            `json:"title_link,omitempty"`
+		Text      string            `json:"text,omitempty"`
+		Fields    []AttachmentField `json:"fields,omitempty"`
+		ThumbUrl  string            `json:"thumb_url,omitempty"`
+	}
+
+	AttachmentField struct {
+		Title string `json:"title"`
+		Value string `json:"value"`
+		Short bool   `json:"short"`
+	}
+)
+
+// NewAttachment renders msg as an attachment within limit characters, the title links to
+// the first link, and all the links follow the text in markdown.
+func NewAttachment(msg Message, limit int) Attachment {
+	att := Attachment{
+		Fallback: Truncate(msg.String(), limit, Runes),
+		Color:    msg.Color.Hex(),
+		Title:    msg.Title,
+		Text:     Truncate(msg.Text, limit/2, Runes),
+		ThumbUrl: msg.Avatar,
+	}
+	for _, f := range msg.Fields {
+		att.Fields = append(att.Fields, AttachmentField{
+			Title: f.Name,
+			Value: f.Value,
+			Short: true,
+		})
+	}
+	if len(msg.Links) > 0 {
+		att.TitleLink = msg.Links[0].URL
+		var links []string
+		for _, link := range msg.Links {
+			links = append(links, fmt.Sprintf("[%s](%s)", link.Title, link.URL))
+		}
+		if len(att.Text) > 0 {
+			links = append([]string{att.Text}, links...)
+		}
+		att.Text = strings.Join(links, "\n")
+	}
+
+	return att
+}
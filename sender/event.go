@@ -0,0 +1,18 @@
+package sender
+
+// The event types of the messages, the senders tell the events apart by them.
+const (
+	StarEvent      = "star"
+	UnstarEvent    = "unstar"
+	DeletedEvent   = "deleted"
+	SuspendedEvent = "suspended"
+	RenamedEvent   = "renamed"
+	NewRepoEvent   = "repo"
+	VIPEvent       = "vip"
+	TrafficEvent   = "traffic"
+	ReferrerEvent  = "referrer"
+	ReleaseEvent   = "release"
+	MilestoneEvent = "milestone"
+	// ErrorEvent is the event type of the errors that the monitors notify.
+	ErrorEvent = "error"
+)
@@ -0,0 +1,106 @@
+package sender
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ColorGreen  Color = "green"
+	ColorRed    Color = "red"
+	ColorBlue   Color = "blue"
+	ColorOrange Color = "orange"
+	ColorGrey   Color = "grey"
+)
+
+type (
+	// Color is the semantic color of a message, each platform maps it to its own.
+	Color string
+
+	// Message is a structured message, rendered natively by RichSender,
+	// and as plain text by the other senders.
+	Message struct {
+		// Event is the type of the event that the message notifies.
+		Event string
+		// Repo is the full name of the repo, like zeromicro/go-zero, if the event is about a repo.
+		Repo   string
+		Title  string
+		Fields []Field
+		Text   string
+		Links  []Link
+		Avatar string
+		Color  Color
+	}
+
+	Field struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	Link struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+	}
+
+	RichSender interface {
+		Sender
+		SendMessage(msg Message) error
+	}
+)
+
+// SendMessage sends msg in the native rich format if s supports it, otherwise in plain text.
+func SendMessage(s Sender, msg Message) error {
+	if rs, ok := s.(RichSender); ok {
+		return rs.SendMessage(msg)
+	}
+
+	return s.Send(msg.String())
+}
+
+func (m *Message) AddField(name string, value any) {
+	m.Fields = append(m.Fields, Field{
+		Name:  name,
+		Value: fmt.Sprint(value),
+	})
+}
+
+func (m *Message) AddLink(title, url string) {
+	m.Links = append(m.Links, Link{
+		Title: title,
+		URL:   url,
+	})
+}
+
+// Hex returns the color in #rrggbb format.
+func (c Color) Hex() string {
+	switch c {
+	case ColorGreen:
+		return "#2eb67d"
+	case ColorRed:
+		return "#e01e5a"
+	case ColorOrange:
+		return "#ecb22e"
+	case ColorGrey:
+		return "#8d8d8d"
+	default:
+		return "#36c5f0"
+	}
+}
+
+func (m Message) String() string {
+	var lines []string
+	if len(m.Title) > 0 {
+		lines = append(lines, m.Title)
+	}
+	for _, field := range m.Fields {
+		lines = append(lines, fmt.Sprintf("%s: %s", field.Name, field.Value))
+	}
+	if len(m.Text) > 0 {
+		lines = append(lines, m.Text)
+	}
+	for _, link := range m.Links {
+		lines = append(lines, fmt.Sprintf("%s: %s", link.Title, link.URL))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,20 @@
+package sender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageString(t *testing.T) {
+	msg := Message{
+		Title: "new star",
+		Color: ColorGreen,
+	}
+	msg.AddField("stars", 12157)
+	msg.AddField("user", "kevwan")
+	msg.AddLink("profile", "https://github.com/kevwan")
+
+	assert.Equal(t, "new star\nstars: 12157\nuser: kevwan\nprofile: https://github.com/kevwan", msg.String())
+	assert.Equal(t, "", Message{}.String())
+}
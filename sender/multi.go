@@ -0,0 +1,125 @@
+package sender
+
+import (
+	"context"
+	"slices"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+const queueSize = 100
+
+type (
+	// Channel is a named sender, there might be several channels of the same platform.
+	Channel struct {
+		Name   string
+		Sender Sender
+	}
+
+	// MultiSender delivers the messages to all the channels, it's a service
+	// that sends in Start, and cancels the sending in Stop on shutdown.
+	MultiSender struct {
+		ctx     context.Context
+		cancel  context.CancelFunc
+		routes  []Route
+		workers []*worker
+	}
+
+	worker struct {
+		Channel
+		queue chan Message
+	}
+)
+
+// NewMultiSender returns a MultiSender of the channels. Each channel has its own queue,
+// so that a failing or paused channel doesn't block the others, nor the monitors.
+// A message goes to the channels of all the routes it matches, or of the default routes
+// if it matches none, otherwise it's dropped. Without routes, it goes to all the channels.
+func NewMultiSender(routes []Route, channels ...Channel) *MultiSender {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &MultiSender{
+		ctx:    ctx,
+		cancel: cancel,
+		routes: routes,
+	}
+	for _, ch := range channels {
+		s.workers = append(s.workers, &worker{
+			Channel: ch,
+			queue:   make(chan Message, queueSize),
+		})
+	}
+
+	return s
+}
+
+func (s *MultiSender) Send(message string) error {
+	return s.SendMessage(Message{
+		Text: message,
+	})
+}
+
+func (s *MultiSender) SendMessage(msg Message) error {
+	var routes []Route
+	for _, route := range s.routes {
+		if !route.Default && route.Match(msg) {
+			routes = append(routes, route)
+		}
+	}
+	if len(routes) == 0 {
+		for _, route := range s.routes {
+			if route.Default {
+				routes = append(routes, route)
+			}
+		}
+	}
+	if len(s.routes) > 0 && len(routes) == 0 {
+		logx.Infof("sender: no route for %s event of %s, message dropped", msg.Event, msg.Repo)
+		return nil
+	}
+
+	for _, w := range s.workers {
+		if len(s.routes) > 0 && !slices.ContainsFunc(routes, func(route Route) bool {
+			return route.Routes(w.Name)
+		}) {
+			continue
+		}
+
+		select {
+		case w.queue <- msg:
+		default:
+			logx.Errorf("sender %s: queue is full, message dropped", w.Name)
+		}
+	}
+
+	return nil
+}
+
+// Start sends the queued messages until Stop is called.
+func (s *MultiSender) Start() {
+	group := threading.NewRoutineGroup()
+	for _, w := range s.workers {
+		group.RunSafe(func() {
+			w.run(s.ctx)
+		})
+	}
+	group.Wait()
+}
+
+// Stop cancels the messages being sent, the queued ones are dropped.
+func (s *MultiSender) Stop() {
+	s.cancel()
+}
+
+func (w *worker) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-w.queue:
+			if err := SendMessageContext(ctx, w.Sender, msg); err != nil {
+				logx.Errorf("sender %s: message dropped, %v", w.Name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package sender
+
+const (
+	DefaultUrgency Urgency = iota
+	LowUrgency
+	HighUrgency
+)
+
+// Urgency is how urgent a message is, the push senders map it to their own priorities.
+type Urgency int
+
+// ClickUrl returns the link to open on a click of the push notification,
+// the profile of the stargazer preferred, otherwise the first link.
+func (m Message) ClickUrl() string {
+	for _, link := range m.Links {
+		if link.Title == "profile" {
+			return link.URL
+		}
+	}
+	if len(m.Links) > 0 {
+		return m.Links[0].URL
+	}
+
+	return ""
+}
+
+// Urgency tells how urgent the message is by its event type, the milestones and the
+// watchlist stars are urgent, the changes of the stargazers are not.
+func (m Message) Urgency() Urgency {
+	switch m.Event {
+	case MilestoneEvent, VIPEvent:
+		return HighUrgency
+	case StarEvent, UnstarEvent, DeletedEvent, SuspendedEvent, RenamedEvent:
+		return LowUrgency
+	default:
+		return DefaultUrgency
+	}
+}
@@ -0,0 +1,23 @@
+package sender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageClickUrl(t *testing.T) {
+	var msg Message
+	assert.Empty(t, msg.ClickUrl())
+	msg.AddLink("repo", "https://github.com/zeromicro/go-zero")
+	assert.Equal(t, "https://github.com/zeromicro/go-zero", msg.ClickUrl())
+	msg.AddLink("profile", "https://github.com/kevwan")
+	assert.Equal(t, "https://github.com/kevwan", msg.ClickUrl())
+}
+
+func TestMessageUrgency(t *testing.T) {
+	assert.Equal(t, HighUrgency, Message{Event: MilestoneEvent}.Urgency())
+	assert.Equal(t, HighUrgency, Message{Event: VIPEvent}.Urgency())
+	assert.Equal(t, LowUrgency, Message{Event: StarEvent}.Urgency())
+	assert.Equal(t, DefaultUrgency, Message{Event: "trending"}.Urgency())
+}
@@ -0,0 +1,283 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/mathx"
+)
+
+const backoffDeviation = 0.2
+
+// DefaultResilientConf is used if the resilience is not configured.
+var DefaultResilientConf = ResilientConf{
+	Timeout:          time.Second * 30,
+	Retries:          3,
+	Backoff:          time.Second * 2,
+	MaxBackoff:       time.Minute,
+	BreakerThreshold: 5,
+	BreakerCooldown:  time.Minute * 5,
+}
+
+type (
+	ResilientConf struct {
+		// Timeout is the timeout of each attempt.
+		Timeout time.Duration `json:"timeout,default=30s"`
+		// Retries is the retries after the first attempt.
+		Retries int `json:"retries,default=3"`
+		// Backoff is doubled on each retry, up to MaxBackoff, with a jitter of 20%.
+		Backoff    time.Duration `json:"backoff,default=2s"`
+		MaxBackoff time.Duration `json:"maxBackoff,default=1m"`
+		// BreakerThreshold is the consecutive failed messages to pause the channel for BreakerCooldown.
+		BreakerThreshold int           `json:"breakerThreshold,default=5"`
+		BreakerCooldown  time.Duration `json:"breakerCooldown,default=5m"`
+		// RateLimit is the messages per minute, 0 for the quota of the platform.
+		RateLimit int `json:"rateLimit,optional"`
+	}
+
+	// RetryAfterError is returned by the senders when the platform asks to retry later.
+	RetryAfterError struct {
+		Err   error
+		After time.Duration
+	}
+
+	// PermanentError is returned by the senders when retrying won't help,
+	// like a rejected request or a wrong config. It's neither retried,
+	// nor counted as a failure of the channel.
+	PermanentError struct {
+		Err error
+	}
+
+	resilientSender struct {
+		name    string
+		c       ResilientConf
+		sender  Sender
+		jitter  mathx.Unstable
+		limiter *limiter
+		// failures and openUntil are the state of the circuit breaker.
+		lock      sync.Mutex
+		failures  int
+		openUntil time.Time
+	}
+
+	limiter struct {
+		lock     sync.Mutex
+		interval time.Duration
+		next     time.Time
+	}
+)
+
+// NewResilientSender returns a Sender that retries with backoff, honors Retry-After,
+// pauses the channel after consecutive failures, and limits the sending rate.
+// It blocks while waiting, so it's meant to run in the queue of the channel,
+// and the waiting ends once the context is done.
+func NewResilientSender(name string, s Sender, c ResilientConf) Sender {
+	rs := &resilientSender{
+		name:   name,
+		c:      c,
+		sender: s,
+		jitter: mathx.NewUnstable(backoffDeviation),
+	}
+	if c.RateLimit > 0 {
+		rs.limiter = &limiter{
+			interval: time.Minute / time.Duration(c.RateLimit),
+		}
+	}
+
+	return rs
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v, retry after %s", e.Err, e.After)
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent marks err as not retryable.
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+// StatusError returns err as permanent if the platform rejected the request with a 4xx,
+// except 408 and 429 that are worth retrying.
+func StatusError(code int, err error) error {
+	if code >= http.StatusBadRequest && code < http.StatusInternalServerError &&
+		code != http.StatusRequestTimeout && code != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+
+	return err
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// ParseRetryAfter parses the Retry-After header, in seconds or as an http date.
+func ParseRetryAfter(value string) time.Duration {
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+
+	return 0
+}
+
+func (s *resilientSender) Send(message string) error {
+	return s.SendContext(context.Background(), message)
+}
+
+func (s *resilientSender) SendContext(ctx context.Context, message string) error {
+	return s.do(ctx, func(ctx context.Context) error {
+		return SendContext(ctx, s.sender, message)
+	})
+}
+
+func (s *resilientSender) SendMessage(msg Message) error {
+	return s.SendMessageContext(context.Background(), msg)
+}
+
+func (s *resilientSender) SendMessageContext(ctx context.Context, msg Message) error {
+	return s.do(ctx, func(ctx context.Context) error {
+		return SendMessageContext(ctx, s.sender, msg)
+	})
+}
+
+func (s *resilientSender) do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := s.waitBreaker(ctx); err != nil {
+		return err
+	}
+
+	var err error
+	for i := 0; i <= s.c.Retries; i++ {
+		if i > 0 {
+			if e := sleep(ctx, s.backoff(i, err)); e != nil {
+				return e
+			}
+		}
+		if s.limiter != nil {
+			if e := s.limiter.wait(ctx); e != nil {
+				return e
+			}
+		}
+
+		if err = s.attempt(ctx, fn); err == nil {
+			s.markSuccess()
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		logx.Errorf("sender %s: attempt %d, %v", s.name, i+1, err)
+		var pe *PermanentError
+		if errors.As(err, &pe) {
+			return err
+		}
+	}
+
+	s.markFailure()
+	return err
+}
+
+func (s *resilientSender) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.c.Timeout <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.c.Timeout)
+	defer cancel()
+
+	return fn(ctx)
+}
+
+// backoff returns the wait before the given retry, Retry-After from the platform takes precedence,
+// both are capped at MaxBackoff.
+func (s *resilientSender) backoff(retry int, err error) time.Duration {
+	var rae *RetryAfterError
+	if errors.As(err, &rae) && rae.After > 0 {
+		if s.c.MaxBackoff > 0 {
+			return min(rae.After, s.c.MaxBackoff)
+		}
+		return rae.After
+	}
+
+	wait := s.c.Backoff << (retry - 1)
+	if wait <= 0 || wait > s.c.MaxBackoff {
+		wait = s.c.MaxBackoff
+	}
+
+	return s.jitter.AroundDuration(wait)
+}
+
+// waitBreaker pauses the channel while the breaker is open, then the next message goes through.
+// If it fails again, the breaker opens again, otherwise it closes.
+func (s *resilientSender) waitBreaker(ctx context.Context) error {
+	s.lock.Lock()
+	wait := time.Until(s.openUntil)
+	s.lock.Unlock()
+
+	return sleep(ctx, wait)
+}
+
+func (s *resilientSender) markSuccess() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.failures = 0
+}
+
+func (s *resilientSender) markFailure() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.failures++
+	if s.c.BreakerThreshold > 0 && s.failures >= s.c.BreakerThreshold {
+		s.openUntil = time.Now().Add(s.c.BreakerCooldown)
+		logx.Errorf("sender %s: %d consecutive failures, paused for %s", s.name, s.failures, s.c.BreakerCooldown)
+	}
+}
+
+func (l *limiter) wait(ctx context.Context) error {
+	l.lock.Lock()
+	now := time.Now()
+	wait := l.next.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	l.next = now.Add(wait + l.interval)
+	l.lock.Unlock()
+
+	return sleep(ctx, wait)
+}
+
+// sleep waits for d, or returns the error of ctx once it's done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,142 @@
+package sender
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingSender struct {
+	failures int
+	err      error
+	calls    []time.Time
+}
+
+func (s *failingSender) Send(_ string) error {
+	s.calls = append(s.calls, time.Now())
+	if len(s.calls) <= s.failures {
+		return s.err
+	}
+
+	return nil
+}
+
+func TestResilientSenderRetry(t *testing.T) {
+	fs := &failingSender{
+		failures: 2,
+		err:      errors.New("bad gateway"),
+	}
+	s := NewResilientSender("test", fs, ResilientConf{
+		Retries:    3,
+		Backoff:    time.Millisecond,
+		MaxBackoff: time.Millisecond * 10,
+	})
+	assert.NoError(t, s.Send("hello"))
+	assert.Len(t, fs.calls, 3)
+
+	fs = &failingSender{
+		failures: 10,
+		err:      errors.New("bad gateway"),
+	}
+	s = NewResilientSender("test", fs, ResilientConf{
+		Retries:    2,
+		Backoff:    time.Millisecond,
+		MaxBackoff: time.Millisecond * 10,
+	})
+	assert.Error(t, s.Send("hello"))
+	assert.Len(t, fs.calls, 3)
+}
+
+func TestResilientSenderRetryAfter(t *testing.T) {
+	fs := &failingSender{
+		failures: 1,
+		err: &RetryAfterError{
+			Err:   errors.New("too many requests"),
+			After: time.Millisecond * 50,
+		},
+	}
+	s := NewResilientSender("test", fs, ResilientConf{
+		Retries:    1,
+		Backoff:    time.Millisecond,
+		MaxBackoff: time.Second,
+	})
+	assert.NoError(t, s.Send("hello"))
+	if assert.Len(t, fs.calls, 2) {
+		assert.GreaterOrEqual(t, fs.calls[1].Sub(fs.calls[0]), time.Millisecond*50)
+	}
+
+	fs = &failingSender{
+		failures: 1,
+		err: &RetryAfterError{
+			Err:   errors.New("too many requests"),
+			After: time.Hour,
+		},
+	}
+	s = NewResilientSender("test", fs, ResilientConf{
+		Retries:    1,
+		Backoff:    time.Millisecond,
+		MaxBackoff: time.Millisecond * 10,
+	})
+	assert.NoError(t, s.Send("hello"))
+	assert.Len(t, fs.calls, 2)
+}
+
+func TestResilientSenderPermanent(t *testing.T) {
+	fs := &failingSender{
+		failures: 10,
+		err:      StatusError(http.StatusBadRequest, errors.New("bad request")),
+	}
+	s := NewResilientSender("test", fs, ResilientConf{
+		Retries:          3,
+		Backoff:          time.Millisecond,
+		MaxBackoff:       time.Millisecond,
+		BreakerThreshold: 1,
+		BreakerCooldown:  time.Hour,
+	})
+	assert.Error(t, s.Send("hello"))
+	assert.Error(t, s.Send("hello"))
+	assert.Len(t, fs.calls, 2)
+
+	assert.NotErrorAs(t, StatusError(http.StatusTooManyRequests, errors.New("too many requests")),
+		new(*PermanentError))
+}
+
+func TestResilientSenderBreaker(t *testing.T) {
+	fs := &failingSender{
+		failures: 2,
+		err:      errors.New("bad gateway"),
+	}
+	s := NewResilientSender("test", fs, ResilientConf{
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Millisecond * 50,
+	})
+	assert.Error(t, s.Send("hello"))
+	assert.Error(t, s.Send("hello"))
+	assert.NoError(t, s.Send("hello"))
+	if assert.Len(t, fs.calls, 3) {
+		assert.GreaterOrEqual(t, fs.calls[2].Sub(fs.calls[1]), time.Millisecond*50)
+	}
+}
+
+func TestResilientSenderRateLimit(t *testing.T) {
+	var fs failingSender
+	s := NewResilientSender("test", &fs, ResilientConf{
+		RateLimit: 60 * 50,
+	})
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, s.Send("hello"))
+	}
+	if assert.Len(t, fs.calls, 3) {
+		assert.GreaterOrEqual(t, fs.calls[2].Sub(fs.calls[0]), time.Millisecond*40)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Second*3, ParseRetryAfter("3"))
+	assert.Equal(t, time.Duration(0), ParseRetryAfter(""))
+	after := ParseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, after > time.Second*50 && after <= time.Minute)
+}
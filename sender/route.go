@@ -0,0 +1,172 @@
+package sender
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var operators = []string{">=", "<=", "!=", ">", "<", "="}
+
+type (
+	// RouteConf routes the matching messages to the channels. Empty conditions match all.
+	RouteConf struct {
+		// Events are the event types to match.
+		Events []string `json:"events,optional"`
+		// Repos are the glob patterns of the repo full names to match, like zeromicro/*.
+		Repos []string `json:"repos,optional"`
+		// Fields are the conditions on the message fields, like followers: ">=1000".
+		// The operators are >=, <=, >, <, != and =, which is the default.
+		Fields map[string]string `json:"fields,optional"`
+		// Channels are the channel names, a name also matches the platforms under it, like name/slack.
+		Channels []string `json:"channels"`
+		// Default routes the messages that match no other routes, it takes no conditions.
+		Default bool `json:"default,optional"`
+	}
+
+	// Route is a parsed RouteConf.
+	Route struct {
+		RouteConf
+		conditions []condition
+	}
+
+	condition struct {
+		field    string
+		operator string
+		value    string
+	}
+)
+
+// ParseRoutes parses the routes, and checks the events and the channels they refer to.
+func ParseRoutes(confs []RouteConf, events, channels []string) ([]Route, error) {
+	var routes []Route
+	for i, c := range confs {
+		route := Route{
+			RouteConf: c,
+		}
+		if len(c.Channels) == 0 {
+			return nil, fmt.Errorf("route %d: no channels", i)
+		}
+		if c.Default && (len(c.Events) > 0 || len(c.Repos) > 0 || len(c.Fields) > 0) {
+			return nil, fmt.Errorf("route %d: default route takes no conditions", i)
+		}
+		for _, event := range c.Events {
+			if !slices.Contains(events, event) {
+				return nil, fmt.Errorf("route %d: unknown event %q", i, event)
+			}
+		}
+		for _, pattern := range c.Repos {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("route %d: bad repo pattern %q, %w", i, pattern, err)
+			}
+		}
+		for _, name := range c.Channels {
+			if !slices.ContainsFunc(channels, func(channel string) bool {
+				return matchChannel(name, channel)
+			}) {
+				return nil, fmt.Errorf("route %d: unknown channel %q", i, name)
+			}
+		}
+		for field, expr := range c.Fields {
+			cond, err := parseCondition(field, expr)
+			if err != nil {
+				return nil, fmt.Errorf("route %d: %w", i, err)
+			}
+			route.conditions = append(route.conditions, cond)
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+// Match tells whether msg matches all the conditions of the route.
+func (r Route) Match(msg Message) bool {
+	if len(r.Events) > 0 && !slices.Contains(r.Events, msg.Event) {
+		return false
+	}
+
+	if len(r.Repos) > 0 && !slices.ContainsFunc(r.Repos, func(pattern string) bool {
+		ok, _ := path.Match(pattern, msg.Repo)
+		return ok
+	}) {
+		return false
+	}
+
+	for _, cond := range r.conditions {
+		if !cond.match(msg) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Routes tells whether the channel is one of the channels of the route.
+func (r Route) Routes(channel string) bool {
+	return slices.ContainsFunc(r.Channels, func(name string) bool {
+		return matchChannel(name, channel)
+	})
+}
+
+func (c condition) match(msg Message) bool {
+	idx := slices.IndexFunc(msg.Fields, func(field Field) bool {
+		return field.Name == c.field
+	})
+	if idx < 0 {
+		return false
+	}
+
+	value := msg.Fields[idx].Value
+	left, lerr := strconv.ParseFloat(value, 64)
+	right, rerr := strconv.ParseFloat(c.value, 64)
+	numeric := lerr == nil && rerr == nil
+
+	switch c.operator {
+	case "=":
+		return value == c.value || numeric && left == right
+	case "!=":
+		return value != c.value && !(numeric && left == right)
+	case ">=":
+		return numeric && left >= right
+	case "<=":
+		return numeric && left <= right
+	case ">":
+		return numeric && left > right
+	case "<":
+		return numeric && left < right
+	default:
+		return false
+	}
+}
+
+func parseCondition(field, expr string) (condition, error) {
+	expr = strings.TrimSpace(expr)
+	for _, op := range operators {
+		if value, ok := strings.CutPrefix(expr, op); ok {
+			cond := condition{
+				field:    field,
+				operator: op,
+				value:    strings.TrimSpace(value),
+			}
+			if op != "=" && op != "!=" {
+				if _, err := strconv.ParseFloat(cond.value, 64); err != nil {
+					return condition{}, fmt.Errorf("field %q: %q needs a number", field, expr)
+				}
+			}
+			return cond, nil
+		}
+	}
+
+	return condition{
+		field:    field,
+		operator: "=",
+		value:    expr,
+	}, nil
+}
+
+func matchChannel(name, channel string) bool {
+	return channel == name || strings.HasPrefix(channel, name+"/")
+}
@@ -0,0 +1,105 @@
+package sender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRoutes(t *testing.T) {
+	events := []string{"star", "unstar"}
+	channels := []string{"community", "maintainers/slack", "maintainers/lark"}
+
+	_, err := ParseRoutes([]RouteConf{{Events: []string{"stars"}, Channels: []string{"community"}}}, events, channels)
+	assert.Error(t, err)
+	_, err = ParseRoutes([]RouteConf{{Channels: []string{"private"}}}, events, channels)
+	assert.Error(t, err)
+	_, err = ParseRoutes([]RouteConf{{Fields: map[string]string{"followers": ">many"}, Channels: []string{"community"}}},
+		events, channels)
+	assert.Error(t, err)
+	_, err = ParseRoutes([]RouteConf{{Repos: []string{"[zeromicro"}, Channels: []string{"community"}}}, events, channels)
+	assert.Error(t, err)
+	_, err = ParseRoutes([]RouteConf{{Events: []string{"star"}, Channels: []string{}}}, events, channels)
+	assert.Error(t, err)
+	_, err = ParseRoutes([]RouteConf{{Events: []string{"star"}, Channels: []string{"community"}, Default: true}},
+		events, channels)
+	assert.Error(t, err)
+
+	routes, err := ParseRoutes([]RouteConf{{Events: []string{"star"}, Channels: []string{"maintainers"}}}, events, channels)
+	assert.NoError(t, err)
+	assert.True(t, routes[0].Routes("maintainers/slack"))
+	assert.False(t, routes[0].Routes("community"))
+}
+
+func TestRouteMatch(t *testing.T) {
+	routes, err := ParseRoutes([]RouteConf{{
+		Events: []string{"star"},
+		Repos:  []string{"zeromicro/*"},
+		Fields: map[string]string{
+			"followers": ">=1000",
+			"user":      "!=kevwan",
+		},
+		Channels: []string{"community"},
+	}}, []string{"star"}, []string{"community"})
+	assert.NoError(t, err)
+
+	msg := Message{
+		Event: "star",
+		Repo:  "zeromicro/go-zero",
+	}
+	msg.AddField("user", "someone")
+	msg.AddField("followers", 1200)
+	assert.True(t, routes[0].Match(msg))
+
+	msg.Fields[1].Value = "999"
+	assert.False(t, routes[0].Match(msg))
+
+	msg.Fields[1].Value = "1000"
+	msg.Repo = "kevwan/stargazers"
+	assert.False(t, routes[0].Match(msg))
+
+	msg.Repo = "zeromicro/go-zero"
+	msg.Fields[0].Value = "kevwan"
+	assert.False(t, routes[0].Match(msg))
+
+	assert.False(t, routes[0].Match(Message{Event: "star", Repo: "zeromicro/go-zero"}))
+}
+
+func TestMultiSenderRoutes(t *testing.T) {
+	routes, err := ParseRoutes([]RouteConf{
+		{Events: []string{"unstar"}, Channels: []string{"maintainers"}},
+		{Events: []string{"star"}, Channels: []string{"community"}},
+		{Events: []string{"milestone"}, Channels: []string{"maintainers", "community"}},
+	}, []string{"star", "unstar", "milestone", "trending"}, []string{"maintainers", "community"})
+	assert.NoError(t, err)
+
+	s := NewMultiSender(routes, Channel{Name: "maintainers"}, Channel{Name: "community"})
+	queued := func(event string) []int {
+		assert.NoError(t, s.SendMessage(Message{Event: event}))
+		var lens []int
+		for _, w := range s.workers {
+			lens = append(lens, len(w.queue))
+			for len(w.queue) > 0 {
+				<-w.queue
+			}
+		}
+		return lens
+	}
+
+	assert.Equal(t, []int{1, 0}, queued("unstar"))
+	assert.Equal(t, []int{0, 1}, queued("star"))
+	assert.Equal(t, []int{1, 1}, queued("milestone"))
+	assert.Equal(t, []int{0, 0}, queued("trending"))
+
+	routes, err = ParseRoutes([]RouteConf{
+		{Events: []string{"star"}, Channels: []string{"community"}},
+		{Default: true, Channels: []string{"maintainers"}},
+	}, []string{"star", "trending"}, []string{"maintainers", "community"})
+	assert.NoError(t, err)
+	s = NewMultiSender(routes, Channel{Name: "maintainers"}, Channel{Name: "community"})
+	assert.Equal(t, []int{0, 1}, queued("star"))
+	assert.Equal(t, []int{1, 0}, queued("trending"))
+
+	s = NewMultiSender(nil, Channel{Name: "maintainers"}, Channel{Name: "community"})
+	assert.Equal(t, []int{1, 1}, queued("trending"))
+}
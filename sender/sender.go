@@ -1,5 +1,61 @@
 package sender
 
-type Sender interface {
-	Send(message string) error
+import (
+	"context"
+
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+type (
+	Sender interface {
+		Send(message string) error
+	}
+
+	// ContextSender is a Sender that stops sending once the context is done.
+	ContextSender interface {
+		RichSender
+		SendContext(ctx context.Context, message string) error
+		SendMessageContext(ctx context.Context, msg Message) error
+	}
+)
+
+// SendContext sends message with ctx, see SendMessageContext for the senders without ctx.
+func SendContext(ctx context.Context, s Sender, message string) error {
+	if cs, ok := s.(ContextSender); ok {
+		return cs.SendContext(ctx, message)
+	}
+
+	return wait(ctx, func() error {
+		return s.Send(message)
+	})
+}
+
+// SendMessageContext sends msg with ctx. The senders that don't support ctx are called
+// in the background, and the call returns once ctx is done, even if they hang.
+func SendMessageContext(ctx context.Context, s Sender, msg Message) error {
+	if cs, ok := s.(ContextSender); ok {
+		return cs.SendMessageContext(ctx, msg)
+	}
+
+	return wait(ctx, func() error {
+		return SendMessage(s, msg)
+	})
+}
+
+func wait(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	threading.GoSafe(func() {
+		done <- fn()
+	})
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
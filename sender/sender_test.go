@@ -0,0 +1,50 @@
+package sender
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type hangingSender struct {
+	release chan struct{}
+}
+
+func (s hangingSender) Send(_ string) error {
+	<-s.release
+	return nil
+}
+
+func TestSendMessageContext(t *testing.T) {
+	hs := hangingSender{release: make(chan struct{})}
+	defer close(hs.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	assert.ErrorIs(t, SendMessageContext(ctx, hs, Message{Text: "hello"}), context.DeadlineExceeded)
+
+	var rs recordSender
+	assert.NoError(t, SendContext(context.Background(), &rs, "hello"))
+	assert.Equal(t, []string{"hello"}, rs.messages)
+}
+
+func TestResilientSenderTimeout(t *testing.T) {
+	hs := hangingSender{release: make(chan struct{})}
+	defer close(hs.release)
+
+	s := NewResilientSender("test", hs, ResilientConf{
+		Timeout:    time.Millisecond * 10,
+		Retries:    1,
+		Backoff:    time.Millisecond,
+		MaxBackoff: time.Millisecond,
+	})
+	start := time.Now()
+	assert.ErrorIs(t, s.Send("hello"), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, SendContext(ctx, s, "hello"), context.Canceled)
+}
@@ -0,0 +1,152 @@
+package sender
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const ellipsis = "…"
+
+// Runes measures the text in characters, and Bytes in bytes, as the platforms count either.
+var (
+	Runes = utf8.RuneCountInString
+	Bytes = func(s string) int { return len(s) }
+)
+
+// Parts sends the parts of the long texts in order. When a part fails, it remembers
+// the parts sent, so that the retry of the same text resumes from the failed part,
+// instead of sending the sent parts again.
+type Parts struct {
+	lock sync.Mutex
+	text string
+	sent int
+}
+
+// Split splits text into ordered parts within limit measured by size,
+// on the line boundaries if possible.
+func Split(text string, limit int, size func(string) int) []string {
+	return SplitFunc(text, limit, size, nil)
+}
+
+// SplitFunc is like Split, safe adjusts the head of a line cut in the middle, and returns
+// the length to keep, like backing off to before an unclosed tag. The head is cut as is
+// if safe returns 0.
+func SplitFunc(text string, limit int, size func(string) int, safe func(head string) int) []string {
+	if size(text) <= limit {
+		return []string{text}
+	}
+
+	var parts []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			parts = append(parts, cur.String())
+			cur.Reset()
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if cur.Len() > 0 && size(cur.String())+size("\n"+line) <= limit {
+			cur.WriteString("\n")
+			cur.WriteString(line)
+			continue
+		}
+
+		flush()
+		for size(line) > limit {
+			head, tail := cut(line, limit, size, safe)
+			parts = append(parts, head)
+			line = tail
+		}
+		cur.WriteString(line)
+	}
+	flush()
+
+	return parts
+}
+
+// Truncate cuts text within limit measured by size, on the line boundaries if possible,
+// and tells how many lines are left out.
+func Truncate(text string, limit int, size func(string) int) string {
+	if size(text) <= limit {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	// reserve the room for the summary of the most lines to leave out
+	room := limit - size(summary(len(lines)))
+	var kept int
+	var total int
+	for i, line := range lines {
+		n := size(line)
+		if i > 0 {
+			n += size("\n")
+		}
+		if total+n > room {
+			break
+		}
+		total += n
+		kept++
+	}
+
+	if kept == 0 {
+		head, _ := cut(text, limit-size(ellipsis), size, nil)
+		return head + ellipsis
+	}
+
+	return strings.Join(lines[:kept], "\n") + summary(len(lines)-kept)
+}
+
+// cut returns the longest head of s within limit, and the rest.
+func cut(s string, limit int, size func(string) int, safe func(string) int) (string, string) {
+	var total int
+	for i, r := range s {
+		total += size(string(r))
+		if total > limit {
+			if i == 0 {
+				// at least one rune, otherwise Split never ends
+				i = utf8.RuneLen(r)
+			} else if safe != nil {
+				if n := safe(s[:i]); n > 0 && n < i {
+					i = n
+				}
+			}
+			return s[:i], s[i:]
+		}
+	}
+
+	return s, ""
+}
+
+// Send sends the parts of text with send, from the failed one if it's a retry of text.
+func (p *Parts) Send(text string, parts []string, send func(part string) error) error {
+	p.lock.Lock()
+	var start int
+	if p.text == text {
+		start = p.sent
+	}
+	p.lock.Unlock()
+
+	for i := start; i < len(parts); i++ {
+		if err := send(parts[i]); err != nil {
+			p.lock.Lock()
+			p.text = text
+			p.sent = i
+			p.lock.Unlock()
+			return err
+		}
+	}
+
+	p.lock.Lock()
+	p.text = ""
+	p.sent = 0
+	p.lock.Unlock()
+
+	return nil
+}
+
+func summary(lines int) string {
+	return fmt.Sprintf("\n%s %d more lines", ellipsis, lines)
+}
@@ -0,0 +1,60 @@
+package sender
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	assert.Equal(t, []string{"hello"}, Split("hello", 10, Runes))
+	assert.Equal(t, []string{"aaa\nbbb", "ccc"}, Split("aaa\nbbb\nccc", 8, Runes))
+	assert.Equal(t, []string{"aaaa", "aaaa", "aa", "bb"}, Split("aaaaaaaaaa\nbb", 4, Runes))
+	assert.Equal(t, []string{"星星", "星"}, Split("星星星", 6, Bytes))
+	assert.Equal(t, []string{"星星", "星"}, Split("星星星", 2, Runes))
+
+	text := strings.Repeat("line of the digest\n", 500)
+	parts := Split(text, 4096, Runes)
+	for _, part := range parts {
+		assert.LessOrEqual(t, Runes(part), 4096)
+	}
+	assert.Equal(t, text, strings.Join(parts, "\n"))
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "hello", Truncate("hello", 10, Runes))
+	assert.Equal(t, "aaaaaaaaaa\n… 2 more lines", Truncate("aaaaaaaaaa\nbbbbbbbbbb\ncccccccccc", 28, Runes))
+	assert.Equal(t, "aaaaaaaaa…", Truncate(strings.Repeat("a", 30), 10, Runes))
+
+	text := strings.Repeat("星星 starred\n", 1000)
+	assert.LessOrEqual(t, len(Truncate(text, 4096, Bytes)), 4096)
+}
+
+func TestSplitFunc(t *testing.T) {
+	safe := func(head string) int {
+		return strings.LastIndexByte(head, ' ') + 1
+	}
+	assert.Equal(t, []string{"aaa ", "bbb cc"}, SplitFunc("aaa bbb cc", 6, Runes, safe))
+	assert.Equal(t, []string{"aaaa", "aa"}, SplitFunc("aaaaaa", 4, Runes, safe))
+}
+
+func TestPartsSend(t *testing.T) {
+	var p Parts
+	var sent []string
+	fail := "b"
+	send := func(part string) error {
+		if part == fail {
+			return errors.New("failed")
+		}
+		sent = append(sent, part)
+		return nil
+	}
+
+	assert.Error(t, p.Send("a\nb\nc", []string{"a", "b", "c"}, send))
+	fail = ""
+	assert.NoError(t, p.Send("a\nb\nc", []string{"a", "b", "c"}, send))
+	assert.NoError(t, p.Send("a\nb\nc", []string{"a", "b", "c"}, send))
+	assert.Equal(t, []string{"a", "b", "c", "a", "b", "c"}, sent)
+}
@@ -0,0 +1,150 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type (
+	// TemplateData is the data that message templates are executed with.
+	TemplateData struct {
+		// Event is the event type, like star, unstar or trending.
+		Event string
+		// Repo is the full name of the repo, like zeromicro/go-zero.
+		Repo  string
+		Title string
+		// Fields holds the fields by name, like {{.Fields.stars}} or {{index .Fields "org stars"}}.
+		Fields map[string]string
+		Text   string
+		// Links holds the link URLs by title, like {{.Links.profile}}.
+		Links  map[string]string
+		Avatar string
+	}
+
+	templateSender struct {
+		Sender
+		templates map[string]*template.Template
+	}
+)
+
+// sampleFields are the fields of the messages, to check the templates with.
+var sampleFields = []string{
+	"blog", "clones", "company", "expect", "followers", "former", "location", "name", "org stars",
+	"referrer", "release", "repo", "starAt", "stars", "time", "today", "uniques", "user", "views", "watch",
+}
+
+// ParseTemplates parses the templates keyed by event type, and executes them with the sample data
+// that has all the fields and links set, so that broken templates fail at startup.
+func ParseTemplates(texts map[string]string, events []string) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template, len(texts))
+	for event, text := range texts {
+		if !slices.Contains(events, event) {
+			return nil, fmt.Errorf("template of unknown event %q, should be one of %s",
+				event, strings.Join(events, ", "))
+		}
+
+		tpl, err := template.New(event).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := tpl.Execute(io.Discard, sampleData(event)); err != nil {
+			return nil, err
+		}
+
+		templates[event] = tpl
+	}
+
+	return templates, nil
+}
+
+// NewTemplateSender returns a Sender that renders the messages of the events in templates
+// with the given templates, and sends the other messages as is.
+// A rendered message only keeps the text, so the rich senders don't show the title
+// and the links as such, the template renders them in the text if wanted.
+func NewTemplateSender(s Sender, templates map[string]*template.Template) Sender {
+	if len(templates) == 0 {
+		return s
+	}
+
+	return &templateSender{
+		Sender:    s,
+		templates: templates,
+	}
+}
+
+func (s *templateSender) SendContext(ctx context.Context, message string) error {
+	return SendContext(ctx, s.Sender, message)
+}
+
+func (s *templateSender) SendMessage(msg Message) error {
+	return s.SendMessageContext(context.Background(), msg)
+}
+
+func (s *templateSender) SendMessageContext(ctx context.Context, msg Message) error {
+	tpl, ok := s.templates[msg.Event]
+	if !ok {
+		return SendMessageContext(ctx, s.Sender, msg)
+	}
+
+	// a template that fails on this message won't succeed on a retry, send it as is instead
+	var builder strings.Builder
+	if err := tpl.Execute(&builder, NewTemplateData(msg)); err != nil {
+		logx.Errorf("sender: template of %s event failed, sent as is, %v", msg.Event, err)
+		return SendMessageContext(ctx, s.Sender, msg)
+	}
+
+	// the template renders the whole text, including the title and links if wanted
+	return SendMessageContext(ctx, s.Sender, Message{
+		Event:  msg.Event,
+		Repo:   msg.Repo,
+		Text:   builder.String(),
+		Avatar: msg.Avatar,
+		Color:  msg.Color,
+	})
+}
+
+func sampleData(event string) TemplateData {
+	msg := Message{
+		Event:  event,
+		Repo:   "zeromicro/go-zero",
+		Title:  "sample",
+		Text:   "sample",
+		Avatar: "https://avatars.githubusercontent.com/u/1",
+		Color:  ColorGreen,
+	}
+	for _, name := range sampleFields {
+		msg.AddField(name, "1")
+	}
+	msg.AddLink("profile", "https://github.com/kevwan")
+	msg.AddLink("repo", "https://github.com/zeromicro/go-zero")
+
+	return NewTemplateData(msg)
+}
+
+// NewTemplateData returns the data of msg for the templates.
+func NewTemplateData(msg Message) TemplateData {
+	data := TemplateData{
+		Event:  msg.Event,
+		Repo:   msg.Repo,
+		Title:  msg.Title,
+		Fields: make(map[string]string, len(msg.Fields)),
+		Text:   msg.Text,
+		Links:  make(map[string]string, len(msg.Links)),
+		Avatar: msg.Avatar,
+	}
+	for _, field := range msg.Fields {
+		data.Fields[field.Name] = field.Value
+	}
+	for _, link := range msg.Links {
+		data.Links[link.Title] = link.URL
+	}
+
+	return data
+}
@@ -0,0 +1,74 @@
+package sender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordSender struct {
+	messages []string
+}
+
+func (s *recordSender) Send(message string) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestParseTemplates(t *testing.T) {
+	_, err := ParseTemplates(map[string]string{"star": "{{.Fields.stars"}, []string{"star"})
+	assert.Error(t, err)
+
+	_, err = ParseTemplates(map[string]string{"stars": "{{.Fields.stars}}"}, []string{"star"})
+	assert.Error(t, err)
+
+	_, err = ParseTemplates(map[string]string{"star": "{{.Nothing}}"}, []string{"star"})
+	assert.Error(t, err)
+
+	_, err = ParseTemplates(map[string]string{"star": "{{.Feilds.user}}"}, []string{"star"})
+	assert.Error(t, err)
+
+	_, err = ParseTemplates(map[string]string{"star": "{{slice .Fields.user 0 1}}"}, []string{"star"})
+	assert.NoError(t, err)
+}
+
+func TestTemplateSender(t *testing.T) {
+	templates, err := ParseTemplates(map[string]string{
+		"star": `⭐ {{.Fields.user}} starred, now {{.Fields.stars}} stars, {{index .Fields "org stars"}} {{.Links.profile}}`,
+	}, []string{"star", "unstar"})
+	assert.NoError(t, err)
+
+	var rs recordSender
+	s := NewTemplateSender(&rs, templates)
+	msg := Message{
+		Event: "star",
+		Title: "new star",
+	}
+	msg.AddField("stars", 100)
+	msg.AddField("user", "kevwan")
+	msg.AddLink("profile", "https://github.com/kevwan")
+	assert.NoError(t, SendMessage(s, msg))
+
+	msg.Event = "unstar"
+	assert.NoError(t, SendMessage(s, msg))
+	assert.Equal(t, []string{
+		"⭐ kevwan starred, now 100 stars,  https://github.com/kevwan",
+		"new star\nstars: 100\nuser: kevwan\nprofile: https://github.com/kevwan",
+	}, rs.messages)
+}
+
+func TestTemplateSenderFallback(t *testing.T) {
+	templates, err := ParseTemplates(map[string]string{
+		"star": "{{slice .Fields.user 0 1}}",
+	}, []string{"star"})
+	assert.NoError(t, err)
+
+	var rs recordSender
+	msg := Message{
+		Event: "star",
+		Title: "new star",
+	}
+	msg.AddField("stars", 100)
+	assert.NoError(t, SendMessage(NewTemplateSender(&rs, templates), msg))
+	assert.Equal(t, []string{"new star\nstars: 100"}, rs.messages)
+}
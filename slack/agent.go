@@ -3,18 +3,31 @@ package slack
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"stargazers/sender"
 
 	"github.com/zeromicro/go-zero/rest/httpc"
 )
 
-const slackPostMessageUrl = "https://slack.com/api/chat.postMessage"
+const (
+	slackPostMessageUrl = "https://slack.com/api/chat.postMessage"
+	dayLayout           = "2006-01-02"
+)
 
 type (
 	app struct {
 		c *Slack
+		// url is the chat.postMessage endpoint.
+		url  string
+		lock sync.Mutex
+		// day and ts are the date and the ts of the parent message of the daily thread.
+		day   string
+		ts    string
+		parts sender.Parts
 	}
 
 	request struct {
@@ -23,35 +36,128 @@ type (
 		Authorization string `header:"Authorization"`
 	}
 
+	richRequest struct {
+		Channel       string       `json:"channel"`
+		Text          string       `json:"text"`
+		Attachments   []attachment `json:"attachments"`
+		Authorization string       `header:"Authorization"`
+	}
+
+	threadRequest struct {
+		Channel       string       `json:"channel"`
+		Text          string       `json:"text"`
+		Attachments   []attachment `json:"attachments"`
+		ThreadTs      string       `json:"thread_ts"`
+		Authorization string       `header:"Authorization"`
+	}
+
 	response struct {
 		OK    bool   `json:"ok"`
 		Error string `json:",optional"`
+		Ts    string `json:"ts,optional"`
 	}
 )
 
 func NewSender(c *Slack) sender.Sender {
-	return &app{c: c}
+	if len(c.WebhookUrl) > 0 && len(c.Token) == 0 {
+		return newWebhook(c)
+	}
+
+	return &app{
+		c:   c,
+		url: slackPostMessageUrl,
+	}
 }
 
 func (a *app) Send(message string) error {
-	req := request{
-		Channel:       a.c.Channel,
-		Text:          message,
-		Authorization: "Bearer " + a.c.Token,
+	return a.SendContext(context.Background(), message)
+}
+
+func (a *app) SendContext(ctx context.Context, message string) error {
+	parts := sender.Split(message, maxTextLength, sender.Runes)
+	return a.parts.Send(message, parts, func(part string) error {
+		_, err := a.post(ctx, request{
+			Channel:       a.c.Channel,
+			Text:          part,
+			Authorization: a.authorization(),
+		})
+		return err
+	})
+}
+
+func (a *app) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *app) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	if a.c.Thread && msg.Event == sender.StarEvent {
+		return a.reply(ctx, msg)
 	}
-	resp, err := httpc.Do(context.Background(), http.MethodPost, slackPostMessageUrl, req)
+
+	_, err := a.post(ctx, richRequest{
+		Channel:       a.c.Channel,
+		Text:          sender.Truncate(msg.String(), maxTextLength, sender.Runes),
+		Attachments:   []attachment{renderAttachment(msg)},
+		Authorization: a.authorization(),
+	})
+	return err
+}
+
+func (a *app) authorization() string {
+	return "Bearer " + a.c.Token
+}
+
+func (a *app) post(ctx context.Context, req any) (string, error) {
+	resp, err := httpc.Do(ctx, http.MethodPost, a.url, req)
 	if err != nil {
-		return err
+		return "", err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		resp.Body.Close()
+		return "", &sender.RetryAfterError{
+			Err:   errors.New("slack: rate limited"),
+			After: sender.ParseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
 
 	var rsp response
 	if err := httpc.Parse(resp, &rsp); err != nil {
-		return err
+		return "", err
 	}
 
 	if !rsp.OK {
-		return errors.New(rsp.Error)
+		return "", errors.New(rsp.Error)
+	}
+
+	return rsp.Ts, nil
+}
+
+// reply posts msg in the thread of today, starting the thread if needed.
+func (a *app) reply(ctx context.Context, msg sender.Message) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	day := time.Now().Format(dayLayout)
+	if day != a.day || len(a.ts) == 0 {
+		ts, err := a.post(ctx, request{
+			Channel:       a.c.Channel,
+			Text:          fmt.Sprintf("Stars on %s", day),
+			Authorization: a.authorization(),
+		})
+		if err != nil {
+			return err
+		}
+
+		a.day = day
+		a.ts = ts
 	}
 
-	return nil
+	_, err := a.post(ctx, threadRequest{
+		Channel:       a.c.Channel,
+		Text:          sender.Truncate(msg.String(), maxTextLength, sender.Runes),
+		Attachments:   []attachment{renderAttachment(msg)},
+		ThreadTs:      a.ts,
+		Authorization: a.authorization(),
+	})
+	return err
 }
@@ -0,0 +1,67 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendMessageThread(t *testing.T) {
+	var reqs []threadRequest
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer xoxb-token", r.Header.Get("Authorization"))
+		var req threadRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqs = append(reqs, req)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"ok": true, "ts": "%d.000100"}`, len(reqs))
+	}))
+	defer svr.Close()
+
+	a := NewSender(&Slack{
+		Token:   "xoxb-token",
+		Channel: "#stars",
+		Thread:  true,
+	}).(*app)
+	a.url = svr.URL
+
+	msg := sender.Message{
+		Event: sender.StarEvent,
+		Title: "new star",
+	}
+	msg.AddField("user", "kevwan")
+	assert.NoError(t, a.SendMessage(msg))
+	assert.NoError(t, a.SendMessage(msg))
+	if assert.Len(t, reqs, 3) {
+		assert.Equal(t, "Stars on "+time.Now().Format(dayLayout), reqs[0].Text)
+		assert.Empty(t, reqs[0].ThreadTs)
+		assert.Equal(t, "1.000100", reqs[1].ThreadTs)
+		assert.Equal(t, "1.000100", reqs[2].ThreadTs)
+		assert.Equal(t, "#stars", reqs[2].Channel)
+		assert.Len(t, reqs[2].Attachments, 1)
+	}
+
+	// a new day starts a new thread
+	a.day = time.Now().AddDate(0, 0, -1).Format(dayLayout)
+	assert.NoError(t, a.SendMessage(msg))
+	if assert.Len(t, reqs, 5) {
+		assert.Empty(t, reqs[3].ThreadTs)
+		assert.Equal(t, "4.000100", reqs[4].ThreadTs)
+	}
+
+	// the other events are not threaded
+	assert.NoError(t, a.SendMessage(sender.Message{
+		Event: sender.UnstarEvent,
+		Title: "unstar",
+	}))
+	if assert.Len(t, reqs, 6) {
+		assert.Empty(t, reqs[5].ThreadTs)
+	}
+}
@@ -0,0 +1,127 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+
+	"stargazers/sender"
+)
+
+const (
+	// https://api.slack.com/reference/block-kit/blocks#section
+	maxSectionFields = 10
+	maxHeaderLength  = 150
+	maxSectionLength = 3000
+	// https://api.slack.com/methods/chat.postMessage#truncating
+	maxTextLength = 4000
+)
+
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+type (
+	attachment struct {
+		Color  string  `json:"color"`
+		Blocks []block `json:"blocks"`
+	}
+
+	block struct {
+		Type      string   `json:"type"`
+		Text      *text    `json:"text,omitempty"`
+		Fields    []text   `json:"fields,omitempty"`
+		Accessory *image   `json:"accessory,omitempty"`
+		Elements  []button `json:"elements,omitempty"`
+	}
+
+	text struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+
+	image struct {
+		Type     string `json:"type"`
+		ImageUrl string `json:"image_url"`
+		AltText  string `json:"alt_text"`
+	}
+
+	button struct {
+		Type string `json:"type"`
+		Text text   `json:"text"`
+		Url  string `json:"url"`
+	}
+)
+
+// renderAttachment renders msg in Block Kit, wrapped in an attachment to show the color.
+func renderAttachment(msg sender.Message) attachment {
+	var blocks []block
+	if len(msg.Title) > 0 {
+		title := msg.Title
+		if runes := []rune(title); len(runes) > maxHeaderLength {
+			title = string(runes[:maxHeaderLength])
+		}
+		blocks = append(blocks, block{
+			Type: "header",
+			Text: &text{
+				Type: "plain_text",
+				Text: title,
+			},
+		})
+	}
+
+	for i := 0; i < len(msg.Fields); i += maxSectionFields {
+		end := min(i+maxSectionFields, len(msg.Fields))
+		section := block{
+			Type: "section",
+		}
+		for _, field := range msg.Fields[i:end] {
+			section.Fields = append(section.Fields, text{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("*%s*\n%s", mrkdwnEscaper.Replace(field.Name), mrkdwnEscaper.Replace(field.Value)),
+			})
+		}
+		if i == 0 && len(msg.Avatar) > 0 {
+			section.Accessory = &image{
+				Type:     "image",
+				ImageUrl: msg.Avatar,
+				AltText:  "avatar",
+			}
+		}
+		blocks = append(blocks, section)
+	}
+
+	if len(msg.Text) > 0 {
+		blocks = append(blocks, block{
+			Type: "section",
+			Text: &text{
+				Type: "mrkdwn",
+				// truncate the raw text measured escaped, not to cut inside an entity
+				Text: mrkdwnEscaper.Replace(sender.Truncate(msg.Text, maxSectionLength, escapedRunes)),
+			},
+		})
+	}
+
+	if len(msg.Links) > 0 {
+		actions := block{
+			Type: "actions",
+		}
+		for _, link := range msg.Links {
+			actions.Elements = append(actions.Elements, button{
+				Type: "button",
+				Text: text{
+					Type: "plain_text",
+					Text: link.Title,
+				},
+				Url: link.URL,
+			})
+		}
+		blocks = append(blocks, actions)
+	}
+
+	return attachment{
+		Color:  msg.Color.Hex(),
+		Blocks: blocks,
+	}
+}
+
+func escapedRunes(s string) int {
+	return sender.Runes(mrkdwnEscaper.Replace(s))
+}
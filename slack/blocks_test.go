@@ -0,0 +1,51 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderAttachment(t *testing.T) {
+	msg := sender.Message{
+		Title:  strings.Repeat("t", 200),
+		Color:  sender.ColorGreen,
+		Text:   "a <b>",
+		Avatar: "https://avatars.githubusercontent.com/u/1",
+	}
+	for i := 0; i < 12; i++ {
+		msg.AddField(fmt.Sprintf("field %d", i), "a & b")
+	}
+	msg.AddLink("profile", "https://github.com/kevwan")
+
+	att := renderAttachment(msg)
+	assert.Equal(t, "#2eb67d", att.Color)
+	if assert.Len(t, att.Blocks, 5) {
+		assert.Equal(t, "header", att.Blocks[0].Type)
+		assert.Equal(t, strings.Repeat("t", maxHeaderLength), att.Blocks[0].Text.Text)
+		assert.Len(t, att.Blocks[1].Fields, maxSectionFields)
+		assert.Equal(t, "*field 0*\na &amp; b", att.Blocks[1].Fields[0].Text)
+		assert.NotNil(t, att.Blocks[1].Accessory)
+		assert.Len(t, att.Blocks[2].Fields, 2)
+		assert.Nil(t, att.Blocks[2].Accessory)
+		assert.Equal(t, "a &lt;b&gt;", att.Blocks[3].Text.Text)
+		assert.Equal(t, "actions", att.Blocks[4].Type)
+		assert.Equal(t, "https://github.com/kevwan", att.Blocks[4].Elements[0].Url)
+	}
+}
+
+func TestRenderAttachmentEscapedText(t *testing.T) {
+	att := renderAttachment(sender.Message{
+		Text: strings.Repeat("&", maxSectionLength),
+	})
+	if assert.Len(t, att.Blocks, 1) {
+		text := att.Blocks[0].Text.Text
+		assert.LessOrEqual(t, sender.Runes(text), maxSectionLength)
+		assert.True(t, strings.HasPrefix(text, "&amp;"))
+		assert.NotContains(t, strings.TrimSuffix(strings.ReplaceAll(text, "&amp;", ""), "…"), "&")
+	}
+}
@@ -1,6 +1,10 @@
 package slack
 
 type Slack struct {
-	Token   string `json:"token"`
-	Channel string `json:"channel"`
+	Token   string `json:"token,optional"`
+	Channel string `json:"channel,optional"`
+	// WebhookUrl is the incoming webhook url, used instead of the token if set.
+	WebhookUrl string `json:"webhookUrl,optional"`
+	// Thread posts the star events of each day as replies in one daily thread, token only.
+	Thread bool `json:"thread,optional"`
 }
@@ -0,0 +1,72 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"stargazers/sender"
+
+	"github.com/zeromicro/go-zero/rest/httpc"
+)
+
+type (
+	webhookApp struct {
+		url   string
+		parts sender.Parts
+	}
+
+	webhookRequest struct {
+		Text string `json:"text"`
+	}
+
+	webhookRichRequest struct {
+		Text        string       `json:"text"`
+		Attachments []attachment `json:"attachments"`
+	}
+)
+
+func newWebhook(c *Slack) sender.Sender {
+	return &webhookApp{url: c.WebhookUrl}
+}
+
+func (a *webhookApp) Send(message string) error {
+	return a.SendContext(context.Background(), message)
+}
+
+func (a *webhookApp) SendContext(ctx context.Context, message string) error {
+	parts := sender.Split(message, maxTextLength, sender.Runes)
+	return a.parts.Send(message, parts, func(part string) error {
+		return a.post(ctx, webhookRequest{
+			Text: part,
+		})
+	})
+}
+
+func (a *webhookApp) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *webhookApp) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	return a.post(ctx, webhookRichRequest{
+		Text:        sender.Truncate(msg.String(), maxTextLength, sender.Runes),
+		Attachments: []attachment{renderAttachment(msg)},
+	})
+}
+
+// post posts req to the incoming webhook, which replies ok or the error in plain text.
+func (a *webhookApp) post(ctx context.Context, req any) error {
+	resp, err := httpc.Do(ctx, http.MethodPost, a.url, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return sender.StatusError(resp.StatusCode, fmt.Errorf("slack webhook: %s %s", resp.Status, body))
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package slack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSendMessage(t *testing.T) {
+	var req webhookRichRequest
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Write([]byte("ok"))
+	}))
+	defer svr.Close()
+
+	s := NewSender(&Slack{
+		WebhookUrl: svr.URL,
+	})
+	assert.IsType(t, &webhookApp{}, s)
+
+	msg := sender.Message{
+		Event: sender.StarEvent,
+		Title: "new star",
+		Color: sender.ColorGreen,
+	}
+	msg.AddField("user", "kevwan")
+	assert.NoError(t, sender.SendMessage(s, msg))
+	assert.Equal(t, msg.String(), req.Text)
+	if assert.Len(t, req.Attachments, 1) {
+		assert.Equal(t, renderAttachment(msg), req.Attachments[0])
+	}
+}
+
+func TestWebhookRejected(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("no_service"))
+	}))
+	defer svr.Close()
+
+	var pe *sender.PermanentError
+	err := NewSender(&Slack{WebhookUrl: svr.URL}).Send("hello")
+	if assert.ErrorAs(t, err, &pe) {
+		assert.Contains(t, err.Error(), "no_service")
+	}
+}
@@ -4,10 +4,10 @@ import (
 	"flag"
 	"log"
 
+	"stargazers/bot"
 	"stargazers/gh"
 	"stargazers/lark"
 	"stargazers/sender"
-	"stargazers/slack"
 	"stargazers/trending"
 	"stargazers/wecom"
 
@@ -19,26 +19,16 @@ var configFile = flag.String("f", "config.yaml", "the config file")
 
 type Config struct {
 	gh.Config
+	SenderConf
 	Trending trending.Trending `json:"trending,optional"`
-	Lark     *lark.Lark        `json:"lark,optional"`
-	Slack    *slack.Slack      `json:"slack,optional"`
-	Wecom    *wecom.Wecom      `json:"wecom,optional"`
-}
-
-func getSender(c Config) sender.Sender {
-	if c.Lark != nil {
-		return lark.NewSender(c.Lark)
-	}
-
-	if c.Slack != nil {
-		return slack.NewSender(c.Slack)
-	}
-
-	if c.Wecom != nil {
-		return wecom.NewSender(c.Wecom)
-	}
-
-	return nil
+	// Channels are the extra senders, several of the same platform are allowed.
+	Channels []ChannelConf `json:"channels,optional"`
+	// Templates are the message templates keyed by event type, applied to all the senders.
+	Templates map[string]string `json:"templates,optional"`
+	// Routes dispatch the messages to the channels by event type, repo and fields.
+	Routes []sender.RouteConf `json:"routes,optional"`
+	// Bot answers the chat commands from the state of the monitors.
+	Bot *bot.Bot `json:"bot,optional"`
 }
 
 func main() {
@@ -46,13 +36,55 @@ func main() {
 
 	var c Config
 	conf.MustLoad(*configFile, &c)
-	sender := getSender(c)
+	sender, err := getSender(c)
+	if err != nil {
+		log.Fatal(err)
+	}
 	if sender == nil {
-		log.Fatal("Set either lark, webhook or slack to receive notifications.")
+		log.Fatal("Set at least one sender or channel to receive notifications.")
 	}
 
 	group := service.NewServiceGroup()
-	group.Add(service.WithStarter(gh.NewMonitor(c.Config, sender)))
-	group.Add(service.WithStarter(trending.NewMonitor(c.Repo, c.Trending, sender)))
+	group.Add(sender)
+	switch {
+	case c.Org != nil:
+		group.Add(service.WithStarter(gh.NewOrgMonitor(c.Config, sender)))
+	case len(c.Repo) > 0:
+		group.Add(service.WithStarter(gh.NewMonitor(c.Config, sender)))
+	default:
+		log.Fatal("Set either repo or org to monitor.")
+	}
+	if len(c.Watchlist) > 0 && len(c.Comparisons) > 0 {
+		group.Add(service.WithStarter(gh.NewWatchMonitor(c.Config, sender)))
+	}
+	if c.Traffic != nil && len(c.Repo) > 0 {
+		group.Add(service.WithStarter(gh.NewTrafficMonitor(c.Config, sender)))
+	}
+	if len(c.Repo) > 0 {
+		group.Add(service.WithStarter(trending.NewMonitor(c.Repo, c.Trending, sender)))
+	}
+	if c.Bot != nil {
+		server, err := newBotServer(c)
+		if err != nil {
+			log.Fatal(err)
+		}
+		group.Add(server)
+	}
 	group.Start()
 }
+
+// newBotServer replies to Lark and Wecom with the top-level apps.
+func newBotServer(c Config) (*bot.Server, error) {
+	var larkReplier, wecomReplier bot.Replier
+	if c.Lark != nil && len(c.Lark.AppId) > 0 {
+		larkReplier = lark.NewReplier(c.Lark)
+	}
+	if c.Wecom != nil && len(c.Wecom.CorpId) > 0 {
+		wecomReplier = wecom.NewReplier(c.Wecom)
+		if c.Bot.Wecom != nil && len(c.Bot.Wecom.CorpId) == 0 {
+			c.Bot.Wecom.CorpId = c.Wecom.CorpId
+		}
+	}
+
+	return bot.NewServer(*c.Bot, larkReplier, wecomReplier)
+}
@@ -0,0 +1,87 @@
+package teams
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"stargazers/sender"
+
+	"github.com/zeromicro/go-zero/rest/httpc"
+)
+
+const (
+	messageType = "message"
+	contentType = "application/vnd.microsoft.card.adaptive"
+	// the webhook payload is limited to 28KB, leave the room for the card
+	maxTextSize = 20 << 10
+)
+
+type (
+	app struct {
+		c     *Teams
+		parts sender.Parts
+	}
+
+	request struct {
+		Type        string       `json:"type"`
+		Attachments []attachment `json:"attachments"`
+	}
+
+	attachment struct {
+		ContentType string `json:"contentType"`
+		Content     card   `json:"content"`
+	}
+)
+
+func NewSender(c *Teams) sender.Sender {
+	return &app{c: c}
+}
+
+func (a *app) Send(message string) error {
+	return a.SendContext(context.Background(), message)
+}
+
+func (a *app) SendContext(ctx context.Context, message string) error {
+	parts := sender.Split(message, maxTextSize, sender.Bytes)
+	return a.parts.Send(message, parts, func(part string) error {
+		return a.post(ctx, renderCard(sender.Message{
+			Text: part,
+		}))
+	})
+}
+
+func (a *app) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *app) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	return a.post(ctx, renderCard(msg))
+}
+
+func (a *app) post(ctx context.Context, c card) error {
+	resp, err := httpc.Do(ctx, http.MethodPost, a.c.WebhookUrl, request{
+		Type: messageType,
+		Attachments: []attachment{
+			{
+				ContentType: contentType,
+				Content:     c,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// incoming webhooks respond 200, and Workflows respond 202
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(resp.Body)
+		return sender.StatusError(resp.StatusCode,
+			fmt.Errorf("teams: %s, %s", resp.Status, strings.TrimSpace(string(body))))
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package teams
+
+import "stargazers/sender"
+
+const (
+	cardSchema  = "http://adaptivecards.io/schemas/adaptive-card.json"
+	cardVersion = "1.4"
+)
+
+type (
+	// https://adaptivecards.io/explorer/
+	card struct {
+		Schema  string    `json:"$schema"`
+		Type    string    `json:"type"`
+		Version string    `json:"version"`
+		Body    []element `json:"body"`
+		Actions []action  `json:"actions,omitempty"`
+	}
+
+	element struct {
+		Type    string    `json:"type"`
+		Text    string    `json:"text,omitempty"`
+		Size    string    `json:"size,omitempty"`
+		Weight  string    `json:"weight,omitempty"`
+		Color   string    `json:"color,omitempty"`
+		Wrap    bool      `json:"wrap,omitempty"`
+		Url     string    `json:"url,omitempty"`
+		Style   string    `json:"style,omitempty"`
+		Width   string    `json:"width,omitempty"`
+		Columns []element `json:"columns,omitempty"`
+		Items   []element `json:"items,omitempty"`
+		Facts   []fact    `json:"facts,omitempty"`
+	}
+
+	fact struct {
+		Title string `json:"title"`
+		Value string `json:"value"`
+	}
+
+	action struct {
+		Type  string `json:"type"`
+		Title string `json:"title"`
+		Url   string `json:"url"`
+	}
+)
+
+func renderCard(msg sender.Message) card {
+	c := card{
+		Schema:  cardSchema,
+		Type:    "AdaptiveCard",
+		Version: cardVersion,
+	}
+
+	if len(msg.Title) > 0 {
+		title := element{
+			Type:   "TextBlock",
+			Text:   msg.Title,
+			Size:   "Medium",
+			Weight: "Bolder",
+			Color:  textColor(msg.Color),
+			Wrap:   true,
+		}
+		if len(msg.Avatar) > 0 {
+			c.Body = append(c.Body, element{
+				Type: "ColumnSet",
+				Columns: []element{
+					{
+						Type:  "Column",
+						Width: "auto",
+						Items: []element{
+							{
+								Type:  "Image",
+								Url:   msg.Avatar,
+								Size:  "Small",
+								Style: "Person",
+							},
+						},
+					},
+					{
+						Type:  "Column",
+						Width: "stretch",
+						Items: []element{title},
+					},
+				},
+			})
+		} else {
+			c.Body = append(c.Body, title)
+		}
+	}
+
+	if len(msg.Fields) > 0 {
+		facts := element{
+			Type: "FactSet",
+		}
+		for _, field := range msg.Fields {
+			facts.Facts = append(facts.Facts, fact{
+				Title: field.Name,
+				Value: field.Value,
+			})
+		}
+		c.Body = append(c.Body, facts)
+	}
+
+	if len(msg.Text) > 0 {
+		c.Body = append(c.Body, element{
+			Type: "TextBlock",
+			Text: sender.Truncate(msg.Text, maxTextSize, sender.Bytes),
+			Wrap: true,
+		})
+	}
+
+	for _, link := range msg.Links {
+		c.Actions = append(c.Actions, action{
+			Type:  "Action.OpenUrl",
+			Title: link.Title,
+			Url:   link.URL,
+		})
+	}
+
+	return c
+}
+
+func textColor(color sender.Color) string {
+	switch color {
+	case sender.ColorGreen:
+		return "Good"
+	case sender.ColorRed:
+		return "Attention"
+	case sender.ColorOrange:
+		return "Warning"
+	case sender.ColorBlue:
+		return "Accent"
+	default:
+		return "Default"
+	}
+}
@@ -0,0 +1,46 @@
+package teams
+
+import (
+	"strings"
+	"testing"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderCard(t *testing.T) {
+	msg := sender.Message{
+		Title:  "new star",
+		Color:  sender.ColorGreen,
+		Avatar: "https://avatars.githubusercontent.com/u/1918356",
+		Text:   "thanks",
+	}
+	msg.AddField("user", "kevwan")
+	msg.AddLink("profile", "https://github.com/kevwan")
+
+	c := renderCard(msg)
+	assert.Equal(t, cardVersion, c.Version)
+	if assert.Len(t, c.Body, 3) {
+		columns := c.Body[0].Columns
+		if assert.Len(t, columns, 2) {
+			assert.Equal(t, msg.Avatar, columns[0].Items[0].Url)
+			assert.Equal(t, "new star", columns[1].Items[0].Text)
+			assert.Equal(t, "Good", columns[1].Items[0].Color)
+		}
+		assert.Equal(t, []fact{{Title: "user", Value: "kevwan"}}, c.Body[1].Facts)
+		assert.Equal(t, "thanks", c.Body[2].Text)
+	}
+	assert.Equal(t, []action{{Type: "Action.OpenUrl", Title: "profile", Url: "https://github.com/kevwan"}},
+		c.Actions)
+
+	c = renderCard(sender.Message{Title: "error", Color: sender.ColorRed})
+	if assert.Len(t, c.Body, 1) {
+		assert.Equal(t, "Attention", c.Body[0].Color)
+	}
+
+	c = renderCard(sender.Message{Text: strings.Repeat("a line\n", 5000)})
+	if assert.Len(t, c.Body, 1) {
+		assert.LessOrEqual(t, len(c.Body[0].Text), maxTextSize)
+	}
+}
@@ -0,0 +1,6 @@
+package teams
+
+type Teams struct {
+	// WebhookUrl is the URL of an incoming webhook or a Workflows trigger.
+	WebhookUrl string `json:"webhookUrl"`
+}
@@ -0,0 +1,130 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"stargazers/sender"
+
+	"github.com/zeromicro/go-zero/core/errorx"
+	"github.com/zeromicro/go-zero/rest/httpc"
+)
+
+// https://core.telegram.org/bots/api#sendmessage, 4096 characters after entities parsing
+const maxTextLength = 4096
+
+type (
+	app struct {
+		c *Telegram
+		// text is the message being sent, and done holds the chats that got it,
+		// so that a retry only sends to the failed chats, from the failed part.
+		lock  sync.Mutex
+		text  string
+		done  map[string]bool
+		parts map[string]*sender.Parts
+	}
+
+	request struct {
+		ChatId                string `json:"chat_id"`
+		Text                  string `json:"text"`
+		ParseMode             string `json:"parse_mode"`
+		DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+	}
+
+	response struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description,optional"`
+	}
+)
+
+func NewSender(c *Telegram) sender.Sender {
+	parts := make(map[string]*sender.Parts)
+	for _, chatId := range c.ChatIds {
+		parts[chatId] = new(sender.Parts)
+	}
+
+	return &app{
+		c:     c,
+		done:  make(map[string]bool),
+		parts: parts,
+	}
+}
+
+func (a *app) Send(message string) error {
+	return a.SendContext(context.Background(), message)
+}
+
+func (a *app) SendContext(ctx context.Context, message string) error {
+	return a.split(ctx, escape(a.c.ParseMode, message))
+}
+
+func (a *app) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *app) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	return a.split(ctx, render(a.c.ParseMode, msg))
+}
+
+// split sends the long text in parts to all the chats, on the line boundaries to keep
+// the entities intact, a long line is cut before the unclosed entities.
+// A failing chat doesn't stop the others.
+func (a *app) split(ctx context.Context, text string) error {
+	parts := sender.SplitFunc(text, maxTextLength, sender.Runes, safeCut(a.c.ParseMode))
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.text != text {
+		a.text = text
+		clear(a.done)
+	}
+
+	var errs errorx.BatchError
+	for _, chatId := range a.c.ChatIds {
+		if a.done[chatId] {
+			continue
+		}
+
+		if err := a.parts[chatId].Send(text, parts, func(part string) error {
+			return a.post(ctx, chatId, part)
+		}); err != nil {
+			errs.Add(fmt.Errorf("chat %s: %w", chatId, err))
+			continue
+		}
+		a.done[chatId] = true
+	}
+
+	err := errs.Err()
+	if err == nil {
+		a.text = ""
+	}
+
+	return err
+}
+
+func (a *app) post(ctx context.Context, chatId, text string) error {
+	url := fmt.Sprintf("%s/bot%s/sendMessage", strings.TrimSuffix(a.c.ApiUrl, "/"), a.c.Token)
+	resp, err := httpc.Do(ctx, http.MethodPost, url, request{
+		ChatId:                chatId,
+		Text:                  text,
+		ParseMode:             a.c.ParseMode,
+		DisableWebPagePreview: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	var rsp response
+	if err := httpc.Parse(resp, &rsp); err != nil {
+		return err
+	}
+	if !rsp.OK {
+		return errors.New(rsp.Description)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package telegram
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendRetriesFailedChats(t *testing.T) {
+	var chats []string
+	failed := map[string]bool{"2": true}
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/bottoken/sendMessage", r.URL.Path)
+		var req request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		chats = append(chats, req.ChatId)
+		w.Header().Set("Content-Type", "application/json")
+		if failed[req.ChatId] {
+			delete(failed, req.ChatId)
+			w.Write([]byte(`{"ok": false, "description": "Too Many Requests"}`))
+			return
+		}
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer svr.Close()
+
+	s := NewSender(&Telegram{
+		Token:     "token",
+		ChatIds:   []string{"1", "2"},
+		ParseMode: "HTML",
+		ApiUrl:    svr.URL,
+	})
+	assert.Error(t, s.Send("hello"))
+	assert.NoError(t, s.Send("hello"))
+	assert.Equal(t, []string{"1", "2", "2"}, chats)
+
+	assert.NoError(t, s.Send("hello"))
+	assert.Equal(t, []string{"1", "2", "2", "1", "2"}, chats)
+}
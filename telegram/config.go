@@ -0,0 +1,8 @@
+package telegram
+
+type Telegram struct {
+	Token     string   `json:"token"`
+	ChatIds   []string `json:"chatIds"`
+	ParseMode string   `json:"parseMode,default=HTML,options=HTML|MarkdownV2"`
+	ApiUrl    string   `json:"apiUrl,default=https://api.telegram.org"`
+}
@@ -0,0 +1,169 @@
+package telegram
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"stargazers/sender"
+)
+
+const markdownV2 = "MarkdownV2"
+
+var (
+	// https://core.telegram.org/bots/api#markdownv2-style
+	markdownEscaper = strings.NewReplacer(
+		`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+		"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+		"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+	)
+	markdownUrlEscaper = strings.NewReplacer(`\`, `\\`, ")", `\)`)
+)
+
+func escape(parseMode, text string) string {
+	if parseMode == markdownV2 {
+		return markdownEscaper.Replace(text)
+	}
+
+	return html.EscapeString(text)
+}
+
+func render(parseMode string, msg sender.Message) string {
+	var lines []string
+	if len(msg.Title) > 0 {
+		lines = append(lines, bold(parseMode, msg.Title))
+	}
+	for _, field := range msg.Fields {
+		lines = append(lines, fmt.Sprintf("%s: %s", bold(parseMode, field.Name), escape(parseMode, field.Value)))
+	}
+	if len(msg.Text) > 0 {
+		lines = append(lines, escape(parseMode, msg.Text))
+	}
+	for _, link := range msg.Links {
+		lines = append(lines, anchor(parseMode, link))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func anchor(parseMode string, link sender.Link) string {
+	if parseMode == markdownV2 {
+		return fmt.Sprintf("[%s](%s)", markdownEscaper.Replace(link.Title), markdownUrlEscaper.Replace(link.URL))
+	}
+
+	return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(link.URL), html.EscapeString(link.Title))
+}
+
+func bold(parseMode, text string) string {
+	if parseMode == markdownV2 {
+		return "*" + markdownEscaper.Replace(text) + "*"
+	}
+
+	return "<b>" + html.EscapeString(text) + "</b>"
+}
+
+// safeCut returns the length of head without the trailing unclosed element, entity or escape,
+// so that a line cut in the middle is still valid markup.
+func safeCut(parseMode string) func(string) int {
+	if parseMode == markdownV2 {
+		return markdownCut
+	}
+
+	return htmlCut
+}
+
+func htmlCut(head string) int {
+	// open is the start of the outermost unclosed element
+	open := -1
+	var depth int
+	for i := 0; i < len(head); i++ {
+		switch head[i] {
+		case '<':
+			end := strings.IndexByte(head[i:], '>')
+			if end < 0 {
+				return cutBefore(open, i)
+			}
+			if strings.HasPrefix(head[i:], "</") {
+				if depth--; depth <= 0 {
+					depth, open = 0, -1
+				}
+			} else {
+				if depth == 0 {
+					open = i
+				}
+				depth++
+			}
+			i += end
+		case '&':
+			if strings.IndexByte(head[i:], ';') < 0 {
+				return cutBefore(open, i)
+			}
+		}
+	}
+
+	return cutBefore(open, len(head))
+}
+
+func markdownCut(head string) int {
+	// open is the start of the outermost unclosed entity, and stack holds the expected ends
+	open := -1
+	var stack []byte
+	push := func(i int, c byte) {
+		if len(stack) == 0 {
+			open = i
+		}
+		stack = append(stack, c)
+	}
+	pop := func() {
+		if stack = stack[:len(stack)-1]; len(stack) == 0 {
+			open = -1
+		}
+	}
+
+	for i := 0; i < len(head); i++ {
+		c := head[i]
+		top := byte(0)
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+		// only the escapes and the end count in urls and code
+		if (top == ')' || top == '`') && c != '\\' && c != top {
+			continue
+		}
+
+		switch c {
+		case '\\':
+			if i == len(head)-1 {
+				return cutBefore(open, i)
+			}
+			i++
+		case '*', '_', '~', '`':
+			if top == c {
+				pop()
+			} else {
+				push(i, c)
+			}
+		case '[':
+			push(i, ']')
+		case ']':
+			if top == ']' {
+				// the link goes on with the url in parentheses
+				stack[len(stack)-1] = ')'
+			}
+		case ')':
+			if top == ')' {
+				pop()
+			}
+		}
+	}
+
+	return cutBefore(open, len(head))
+}
+
+func cutBefore(open, i int) int {
+	if open >= 0 {
+		return open
+	}
+
+	return i
+}
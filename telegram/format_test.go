@@ -0,0 +1,43 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	msg := sender.Message{
+		Title: "new star",
+	}
+	msg.AddField("user", "a_b")
+	msg.AddField("time", "10-26 22:52:56")
+	msg.AddLink("profile", "https://github.com/a_b?x=(1)")
+
+	assert.Equal(t, "<b>new star</b>\n<b>user</b>: a_b\n<b>time</b>: 10-26 22:52:56\n"+
+		`<a href="https://github.com/a_b?x=(1)">profile</a>`, render("HTML", msg))
+	assert.Equal(t, "*new star*\n*user*: a\\_b\n*time*: 10\\-26 22:52:56\n"+
+		`[profile](https://github.com/a_b?x=(1\))`, render(markdownV2, msg))
+	assert.Equal(t, "a &lt;b&gt; &amp; c", escape("HTML", "a <b> & c"))
+	assert.Equal(t, `1\.5\!`, escape(markdownV2, "1.5!"))
+}
+
+func TestSafeCut(t *testing.T) {
+	assert.Equal(t, 6, htmlCut("a &lt;&am"))
+	assert.Equal(t, 2, htmlCut(`a <a href="https://github.com">prof`))
+	assert.Equal(t, 2, htmlCut(`a <a hr`))
+	assert.Equal(t, 19, htmlCut(`<b>new star</b> abc`))
+
+	assert.Equal(t, 4, markdownCut(`1\.5\`))
+	assert.Equal(t, 2, markdownCut(`a *new sta`))
+	assert.Equal(t, 2, markdownCut(`a [profile](https://github.com/a_b?x=(1\)`))
+	assert.Equal(t, 18, markdownCut(`*new star* a\_b 10`))
+
+	text := strings.Repeat("a &amp; b ", 1000)
+	for _, part := range sender.SplitFunc(text, maxTextLength, sender.Runes, safeCut("HTML")) {
+		assert.Equal(t, len(part), htmlCut(part))
+	}
+}
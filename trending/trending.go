@@ -2,7 +2,9 @@ package trending
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"stargazers/sender"
@@ -16,8 +18,15 @@ const (
 	dailyRange    = "daily"
 	weeklyRange   = "weekly"
 	monthlyRange  = "monthly"
+	// TrendingEvent is the event type of the trending notifications.
+	TrendingEvent = "trending"
 )
 
+var current = struct {
+	sync.RWMutex
+	positions []Position
+}{}
+
 type (
 	Trending struct {
 		Language   string   `json:"language,default=Go"`
@@ -55,49 +64,48 @@ func (m *Monitor) Start() {
 	ticker := time.NewTicker(checkInterval)
 	defer ticker.Stop()
 
-	var buf strings.Builder
 	for range ticker.C {
-		buf.Reset()
 		positions := m.findInTrending()
 		if !m.checkIfChanged(positions) {
 			continue
 		}
 
 		m.previous = positions
+		current.Lock()
+		current.positions = positions
+		current.Unlock()
 		if len(positions) == 0 {
 			continue
 		}
 
-		buf.WriteString(fmt.Sprintln(m.name))
+		msg := sender.Message{
+			Event: TrendingEvent,
+			Repo:  m.author + "/" + m.name,
+			Title: m.name,
+			Color: sender.ColorOrange,
+		}
 		for _, pos := range positions {
-			switch pos.Range {
-			case dailyRange:
-				if pos.Lang == "" {
-					buf.WriteString(fmt.Sprintf("daily trending: %d\n", pos.Pos))
-				} else {
-					buf.WriteString(fmt.Sprintf("%s daily trending: %d\n", pos.Lang, pos.Pos))
-				}
-			case weeklyRange:
-				if pos.Lang == "" {
-					buf.WriteString(fmt.Sprintf("weekly trending: %d\n", pos.Pos))
-				} else {
-					buf.WriteString(fmt.Sprintf("%s weekly trending: %d\n", pos.Lang, pos.Pos))
-				}
-			case monthlyRange:
-				if pos.Lang == "" {
-					buf.WriteString(fmt.Sprintf("monthly trending: %d\n", pos.Pos))
-				} else {
-					buf.WriteString(fmt.Sprintf("%s monthly trending: %d\n", pos.Lang, pos.Pos))
-				}
+			name := pos.Range + " trending"
+			if pos.Lang != "" {
+				name = pos.Lang + " " + name
 			}
+			msg.AddField(name, pos.Pos)
 		}
+		msg.AddLink("repo", fmt.Sprintf("https://github.com/%s/%s", m.author, m.name))
 
-		if err := m.sender.Send(buf.String()); err != nil {
+		if err := sender.SendMessage(m.sender, msg); err != nil {
 			logx.Error(err)
 		}
 	}
 }
 
+// Positions returns the latest positions of the repo in trending, for the bot commands.
+func Positions() []Position {
+	current.RLock()
+	defer current.RUnlock()
+	return slices.Clone(current.positions)
+}
+
 func (m *Monitor) findInTrending() (positions []Position) {
 	trend := trending.NewTrending()
 	for _, dateRange := range m.dateRanges {
@@ -113,7 +121,13 @@ func (m *Monitor) findInTrending() (positions []Position) {
 				repos, err = trend.GetProjects(trending.TimeMonth, lang)
 			}
 			if err != nil {
-				if e := m.sender.Send(err.Error()); err != nil {
+				if e := sender.SendMessage(m.sender, sender.Message{
+					Event: sender.ErrorEvent,
+					Repo:  m.author + "/" + m.name,
+					Title: "trending",
+					Text:  err.Error(),
+					Color: sender.ColorRed,
+				}); e != nil {
 					logx.Error(e)
 				}
 				return
@@ -0,0 +1,166 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/zeromicro/go-zero/rest/httpc"
+)
+
+var funcs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		content, err := json.Marshal(v)
+		return string(content), err
+	},
+}
+
+type (
+	app struct {
+		c    *Webhook
+		body *template.Template
+	}
+
+	payload struct {
+		Event  string         `json:"event"`
+		Title  string         `json:"title,omitempty"`
+		Fields []sender.Field `json:"fields,omitempty"`
+		Text   string         `json:"text,omitempty"`
+		Links  []sender.Link  `json:"links,omitempty"`
+		Avatar string         `json:"avatar,omitempty"`
+		Color  sender.Color   `json:"color,omitempty"`
+	}
+)
+
+// NewSender returns a webhook sender, the body template is checked to render valid JSON.
+func NewSender(c *Webhook) (sender.Sender, error) {
+	a := &app{c: c}
+	if len(c.Body) == 0 {
+		return a, nil
+	}
+
+	body, err := template.New("body").Funcs(funcs).Option("missingkey=zero").Parse(c.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	a.body = body
+	content, err := a.render(sender.Message{Event: "star", Title: "sample"})
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(content) {
+		return nil, fmt.Errorf("webhook body is not valid JSON: %s", content)
+	}
+
+	return a, nil
+}
+
+func (a *app) Send(message string) error {
+	return a.SendContext(context.Background(), message)
+}
+
+func (a *app) SendContext(ctx context.Context, message string) error {
+	return a.SendMessageContext(ctx, sender.Message{
+		Text: message,
+	})
+}
+
+func (a *app) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *app) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	body, err := a.render(msg)
+	if err != nil {
+		return sender.Permanent(err)
+	}
+
+	return a.post(ctx, body)
+}
+
+// post sends the body, the rejected requests are not worth retrying.
+func (a *app) post(ctx context.Context, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, a.c.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, a.c.Method, a.c.Url, bytes.NewReader(body))
+	if err != nil {
+		return sender.Permanent(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range a.c.Headers {
+		req.Header.Set(k, v)
+	}
+	if len(a.c.Secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(a.c.TimestampHeader, timestamp)
+		req.Header.Set(a.c.SignatureHeader, sign(a.c.Secret, timestamp, body))
+	}
+
+	resp, err := httpc.DoRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	content, _ := io.ReadAll(resp.Body)
+	err = fmt.Errorf("webhook: %s, %s", resp.Status, strings.TrimSpace(string(content)))
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &sender.RetryAfterError{
+			Err:   err,
+			After: sender.ParseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	return sender.StatusError(resp.StatusCode, err)
+}
+
+func (a *app) render(msg sender.Message) ([]byte, error) {
+	if a.body == nil {
+		return json.Marshal(payload{
+			Event:  msg.Event,
+			Title:  msg.Title,
+			Fields: msg.Fields,
+			Text:   msg.Text,
+			Links:  msg.Links,
+			Avatar: msg.Avatar,
+			Color:  msg.Color,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := a.body.Execute(&buf, sender.NewTemplateData(msg)); err != nil {
+		return nil, err
+	}
+	if buf.Len() == 0 {
+		return nil, errors.New("webhook body is empty")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
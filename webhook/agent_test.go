@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook(t *testing.T) {
+	var calls int
+	var body, signature, timestamp, token string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		case 2:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		content, _ := io.ReadAll(r.Body)
+		body = string(content)
+		signature = r.Header.Get("X-Stargazers-Signature-256")
+		timestamp = r.Header.Get("X-Stargazers-Timestamp")
+		token = r.Header.Get("X-Token")
+	}))
+	defer svr.Close()
+
+	s, err := NewSender(&Webhook{
+		Url:             svr.URL,
+		Method:          http.MethodPost,
+		Headers:         map[string]string{"X-Token": "abc"},
+		Body:            `{"text": {{json (printf "%s starred" .Fields.user)}}}`,
+		Secret:          "secret",
+		SignatureHeader: "X-Stargazers-Signature-256",
+		TimestampHeader: "X-Stargazers-Timestamp",
+		Timeout:         time.Second,
+	})
+	assert.NoError(t, err)
+
+	msg := sender.Message{Event: "star"}
+	msg.AddField("user", `"kevwan"`)
+	var pe *sender.PermanentError
+	assert.NotErrorAs(t, sender.SendMessage(s, msg), &pe)
+	assert.ErrorAs(t, sender.SendMessage(s, msg), &pe)
+	assert.NoError(t, sender.SendMessage(s, msg))
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, `{"text": "\"kevwan\" starred"}`, body)
+	assert.Equal(t, sign("secret", timestamp, []byte(body)), signature)
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), time.Unix(sent, 0), time.Minute)
+	assert.Equal(t, "abc", token)
+}
+
+func TestNewSenderInvalidBody(t *testing.T) {
+	_, err := NewSender(&Webhook{Body: `{"text": {{.Fields.user}}}`})
+	assert.Error(t, err)
+
+	_, err = NewSender(&Webhook{Body: `{{.Nothing}}`})
+	assert.Error(t, err)
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, "sha256=1898b1f7ee8ff2fe446237422bd9b3afcdb1fff758351d6ee4236bc6f1530852",
+		sign("secret", "1700000000", []byte(`{"text":"hello"}`)))
+}
@@ -0,0 +1,18 @@
+package webhook
+
+import "time"
+
+type Webhook struct {
+	Url     string            `json:"url"`
+	Method  string            `json:"method,default=POST"`
+	Headers map[string]string `json:"headers,optional"`
+	// Body is the text/template of the JSON body, the message is sent as is if not set.
+	Body string `json:"body,optional"`
+	// Secret signs the timestamp and the body, joined by a dot, with HMAC-SHA256 in SignatureHeader,
+	// like sha256=<hex>. The unix timestamp in seconds is sent in TimestampHeader,
+	// so that the receivers can reject the replayed deliveries.
+	Secret          string        `json:"secret,optional"`
+	SignatureHeader string        `json:"signatureHeader,default=X-Stargazers-Signature-256"`
+	TimestampHeader string        `json:"timestampHeader,default=X-Stargazers-Timestamp"`
+	Timeout         time.Duration `json:"timeout,default=10s"`
+}
@@ -1,8 +1,19 @@
 package wecom
 
 type Wecom struct {
-	CorpId     string   `json:"corpId"`
-	CorpSecret string   `json:"corpSecret"`
-	AgentId    int      `json:"agentId"`
-	Receivers  []string `json:"receivers"`
+	CorpId     string   `json:"corpId,optional"`
+	CorpSecret string   `json:"corpSecret,optional"`
+	AgentId    int      `json:"agentId,optional"`
+	Receivers  []string `json:"receivers,optional"`
+	// Key is the key of the group robot webhook, used instead of the corp application if set.
+	Key string `json:"key,optional"`
+	// MsgType is the message type of the group robot.
+	MsgType string `json:"msgType,default=markdown,options=text|markdown"`
+	// Image sends the avatar of the stargazer as an image message after the message.
+	Image bool `json:"image,optional"`
+	// MentionedList is the user IDs to mention, @all to mention everyone,
+	// the messages are sent as text with @all, which markdown doesn't support.
+	MentionedList []string `json:"mentionedList,optional"`
+	// MentionedMobiles is the mobiles to mention, only for text messages.
+	MentionedMobiles []string `json:"mentionedMobiles,optional"`
 }
@@ -0,0 +1,91 @@
+package wecom
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"stargazers/sender"
+)
+
+const (
+	textCardButton = "详情"
+	// the description of textcards is limited to 512 bytes
+	maxTextCardSize = 512
+)
+
+type textCard struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Url         string `json:"url"`
+	BtnTxt      string `json:"btntxt"`
+}
+
+// renderMarkdown renders msg in the markdown subset that Wecom supports, within limit bytes.
+func renderMarkdown(msg sender.Message, limit int) string {
+	var lines []string
+	if len(msg.Title) > 0 {
+		lines = append(lines, fmt.Sprintf(`**<font color="%s">%s</font>**`, fontColor(msg.Color), msg.Title))
+	}
+	for _, field := range msg.Fields {
+		lines = append(lines, fmt.Sprintf(`> %s: <font color="comment">%s</font>`, field.Name, field.Value))
+	}
+	if len(msg.Text) > 0 {
+		lines = append(lines, msg.Text)
+	}
+	for _, link := range msg.Links {
+		lines = append(lines, fmt.Sprintf("[%s](%s)", link.Title, link.URL))
+	}
+
+	return sender.Truncate(strings.Join(lines, "\n"), limit, sender.Bytes)
+}
+
+// renderTextCard renders msg as a textcard, which opens the first link when clicked.
+func renderTextCard(msg sender.Message) textCard {
+	var lines []string
+	var size int
+	for _, field := range msg.Fields {
+		line := fmt.Sprintf(`<div class="normal">%s: %s</div>`,
+			html.EscapeString(field.Name), html.EscapeString(field.Value))
+		if size+len(line) > maxTextCardSize {
+			break
+		}
+		lines = append(lines, line)
+		size += len(line)
+	}
+	if len(msg.Text) > 0 && size < maxTextCardSize {
+		const wrapper = `<div class="gray"></div>`
+		if room := maxTextCardSize - size - len(wrapper); room > 0 {
+			// measure the text escaped, which grows it
+			text := sender.Truncate(msg.Text, room, escapedSize)
+			lines = append(lines, fmt.Sprintf(`<div class="gray">%s</div>`, html.EscapeString(text)))
+		}
+	}
+
+	title := msg.Title
+	if len(title) == 0 {
+		title = "stargazers"
+	}
+
+	return textCard{
+		Title:       title,
+		Description: strings.Join(lines, ""),
+		Url:         msg.Links[0].URL,
+		BtnTxt:      textCardButton,
+	}
+}
+
+func escapedSize(s string) int {
+	return len(html.EscapeString(s))
+}
+
+func fontColor(color sender.Color) string {
+	switch color {
+	case sender.ColorGreen:
+		return "info"
+	case sender.ColorRed, sender.ColorOrange:
+		return "warning"
+	default:
+		return "comment"
+	}
+}
@@ -0,0 +1,39 @@
+package wecom
+
+import (
+	"strings"
+	"testing"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	msg := sender.Message{
+		Title: "new star",
+		Color: sender.ColorGreen,
+		Text:  "hello",
+	}
+	msg.AddField("user", "kevwan")
+	msg.AddLink("profile", "https://github.com/kevwan")
+
+	assert.Equal(t, `**<font color="info">new star</font>**`+"\n"+
+		`> user: <font color="comment">kevwan</font>`+"\n"+
+		"hello\n[profile](https://github.com/kevwan)", renderMarkdown(msg, maxMarkdownSize))
+	assert.LessOrEqual(t, len(renderMarkdown(msg, 40)), 40)
+}
+
+func TestRenderTextCard(t *testing.T) {
+	msg := sender.Message{
+		Text: strings.Repeat("<", 1000),
+	}
+	msg.AddField("user", "kevwan")
+	msg.AddLink("profile", "https://github.com/kevwan")
+
+	card := renderTextCard(msg)
+	assert.Equal(t, "stargazers", card.Title)
+	assert.Equal(t, "https://github.com/kevwan", card.Url)
+	assert.True(t, strings.HasPrefix(card.Description, `<div class="normal">user: kevwan</div><div class="gray">&lt;`))
+	assert.LessOrEqual(t, len(card.Description), maxTextCardSize)
+}
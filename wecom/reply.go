@@ -0,0 +1,46 @@
+package wecom
+
+import (
+	"context"
+	"sync"
+
+	"stargazers/sender"
+)
+
+// Replier replies to the users with the corp application, used by the bot commands.
+type Replier struct {
+	lock sync.Mutex
+	app  *app
+}
+
+func NewReplier(c *Wecom) *Replier {
+	return &Replier{
+		app: &app{c: c},
+	}
+}
+
+func (r *Replier) Reply(ctx context.Context, user, text string) error {
+	// the access token is cached in app without locking
+	r.lock.Lock()
+	token, err := r.app.getToken(ctx)
+	r.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, part := range sender.Split(text, maxTextSize, sender.Bytes) {
+		if err := r.app.post(ctx, request{
+			AccessToken: token,
+			AgentID:     r.app.c.AgentId,
+			MsgType:     messageType,
+			ToUser:      user,
+			Text: textBody{
+				Content: part,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
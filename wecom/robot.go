@@ -0,0 +1,198 @@
+package wecom
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"stargazers/sender"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/rest/httpc"
+)
+
+const (
+	imageMessageType = "image"
+	robotUrl         = "https://qyapi.weixin.qq.com/cgi-bin/webhook/send"
+	mentionAll       = "@all"
+	// https://developer.work.weixin.qq.com/document/path/91770, images up to 2M
+	maxImageSize = 2 << 20
+)
+
+type (
+	robot struct {
+		c     *Wecom
+		parts sender.Parts
+	}
+
+	robotTextBody struct {
+		Content          string   `json:"content"`
+		MentionedList    []string `json:"mentioned_list,omitempty"`
+		MentionedMobiles []string `json:"mentioned_mobile_list,omitempty"`
+	}
+
+	imageBody struct {
+		Base64 string `json:"base64"`
+		Md5    string `json:"md5"`
+	}
+
+	robotTextRequest struct {
+		Key     string        `form:"key"`
+		MsgType string        `json:"msgtype"`
+		Text    robotTextBody `json:"text"`
+	}
+
+	robotMarkdownRequest struct {
+		Key      string   `form:"key"`
+		MsgType  string   `json:"msgtype"`
+		Markdown textBody `json:"markdown"`
+	}
+
+	robotImageRequest struct {
+		Key     string    `form:"key"`
+		MsgType string    `json:"msgtype"`
+		Image   imageBody `json:"image"`
+	}
+)
+
+func newRobot(c *Wecom) sender.Sender {
+	return &robot{c: c}
+}
+
+func (r *robot) Send(text string) error {
+	return r.SendContext(context.Background(), text)
+}
+
+func (r *robot) SendContext(ctx context.Context, text string) error {
+	parts := sender.Split(text, maxTextSize, sender.Bytes)
+	return r.parts.Send(text, parts, func(part string) error {
+		return r.sendText(ctx, part)
+	})
+}
+
+func (r *robot) SendMessage(msg sender.Message) error {
+	return r.SendMessageContext(context.Background(), msg)
+}
+
+// SendMessageContext sends msg as markdown, or as text if configured or mentioning all,
+// which markdown messages don't support. The avatar failures are logged, not returned,
+// otherwise the retry would send the text again.
+func (r *robot) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	var err error
+	if r.c.MsgType == messageType || slices.Contains(r.c.MentionedList, mentionAll) {
+		err = r.SendContext(ctx, msg.String())
+	} else {
+		err = r.post(ctx, robotMarkdownRequest{
+			Key:     r.c.Key,
+			MsgType: markdownMessageType,
+			Markdown: textBody{
+				Content: withMentions(msg, r.c.MentionedList),
+			},
+		})
+	}
+	if err != nil || !r.c.Image || len(msg.Avatar) == 0 {
+		return err
+	}
+
+	if err := r.sendImage(ctx, msg.Avatar); err != nil {
+		logx.Errorf("wecom robot: failed to send avatar %s, %v", msg.Avatar, err)
+	}
+
+	return nil
+}
+
+func (r *robot) sendImage(ctx context.Context, url string) error {
+	data, err := download(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	sum := md5.Sum(data)
+	return r.post(ctx, robotImageRequest{
+		Key:     r.c.Key,
+		MsgType: imageMessageType,
+		Image: imageBody{
+			Base64: base64.StdEncoding.EncodeToString(data),
+			Md5:    hex.EncodeToString(sum[:]),
+		},
+	})
+}
+
+func (r *robot) sendText(ctx context.Context, text string) error {
+	return r.post(ctx, robotTextRequest{
+		Key:     r.c.Key,
+		MsgType: messageType,
+		Text: robotTextBody{
+			Content:          text,
+			MentionedList:    r.c.MentionedList,
+			MentionedMobiles: r.c.MentionedMobiles,
+		},
+	})
+}
+
+func (r *robot) post(ctx context.Context, req any) error {
+	resp, err := httpc.Do(ctx, http.MethodPost, robotUrl, req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(resp.Status)
+	}
+
+	var rsp response
+	if err := httpc.Parse(resp, &rsp); err != nil {
+		return err
+	}
+	if rsp.Code != 0 {
+		return fmt.Errorf("wecom robot: %d %s", rsp.Code, rsp.Msg)
+	}
+
+	return nil
+}
+
+func download(ctx context.Context, url string) ([]byte, error) {
+	resp, err := httpc.Do(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageSize {
+		return nil, fmt.Errorf("download %s: image larger than 2M", url)
+	}
+
+	return data, nil
+}
+
+// withMentions renders msg as markdown with the mentions of the user IDs appended,
+// the room of the mentions is reserved within the size limit.
+// Markdown messages only support <@userid>, not @all.
+func withMentions(msg sender.Message, users []string) string {
+	var mentions []string
+	for _, user := range users {
+		if user != mentionAll {
+			mentions = append(mentions, fmt.Sprintf("<@%s>", user))
+		}
+	}
+	if len(mentions) == 0 {
+		return renderMarkdown(msg, maxMarkdownSize)
+	}
+
+	suffix := "\n" + strings.Join(mentions, " ")
+	return renderMarkdown(msg, maxMarkdownSize-len(suffix)) + suffix
+}
@@ -0,0 +1,20 @@
+package wecom
+
+import (
+	"strings"
+	"testing"
+
+	"stargazers/sender"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMentions(t *testing.T) {
+	msg := sender.Message{Text: "hello"}
+	assert.Equal(t, "hello", withMentions(msg, nil))
+	assert.Equal(t, "hello\n<@kevin>", withMentions(msg, []string{"kevin", "@all"}))
+
+	msg.Text = strings.Repeat("a line of text\n", 1000)
+	assert.LessOrEqual(t, len(withMentions(msg, []string{"kevin"})), maxMarkdownSize)
+	assert.True(t, strings.HasSuffix(withMentions(msg, []string{"kevin"}), "\n<@kevin>"))
+}
@@ -16,8 +16,14 @@ import (
 )
 
 const (
-	messageType     = "text"
-	refreshTokenUrl = "https://qyapi.weixin.qq.com/cgi-bin/gettoken"
+	messageType         = "text"
+	markdownMessageType = "markdown"
+	textCardMessageType = "textcard"
+	refreshTokenUrl     = "https://qyapi.weixin.qq.com/cgi-bin/gettoken"
+	sendMessageUrl      = "https://qyapi.weixin.qq.com/cgi-bin/message/send"
+	// https://developer.work.weixin.qq.com/document/path/90236, in bytes
+	maxTextSize     = 2048
+	maxMarkdownSize = 4096
 )
 
 type (
@@ -29,6 +35,7 @@ type (
 	app struct {
 		c     *Wecom
 		token accessToken
+		parts sender.Parts
 	}
 
 	textBody struct {
@@ -44,6 +51,24 @@ type (
 		Text        textBody `json:"text"`
 	}
 
+	markdownRequest struct {
+		AccessToken string   `form:"access_token"`
+		AgentID     int      `json:"agentid"`
+		MsgType     string   `json:"msgtype"`
+		ToUser      string   `json:"touser"`
+		ToParty     string   `json:"toparty"`
+		Markdown    textBody `json:"markdown"`
+	}
+
+	textCardRequest struct {
+		AccessToken string   `form:"access_token"`
+		AgentID     int      `json:"agentid"`
+		MsgType     string   `json:"msgtype"`
+		ToUser      string   `json:"touser"`
+		ToParty     string   `json:"toparty"`
+		TextCard    textCard `json:"textcard"`
+	}
+
 	response struct {
 		Code int    `json:"errcode"`
 		Msg  string `json:"errmsg"`
@@ -58,26 +83,73 @@ type (
 )
 
 func NewSender(c *Wecom) sender.Sender {
+	if len(c.Key) > 0 {
+		return newRobot(c)
+	}
+
 	return &app{c: c}
 }
 
 func (a *app) Send(text string) error {
+	return a.SendContext(context.Background(), text)
+}
+
+func (a *app) SendContext(ctx context.Context, text string) error {
 	toUsers := strings.Join(a.c.Receivers, "|")
-	token, err := a.getToken()
+	token, err := a.getToken(ctx)
 	if err != nil {
 		return err
 	}
 
-	req := request{
+	parts := sender.Split(text, maxTextSize, sender.Bytes)
+	return a.parts.Send(text, parts, func(part string) error {
+		return a.post(ctx, request{
+			AccessToken: token,
+			AgentID:     a.c.AgentId,
+			MsgType:     messageType,
+			ToUser:      toUsers,
+			Text: textBody{
+				Content: part,
+			},
+		})
+	})
+}
+
+// SendMessage sends msg as a textcard if it has links, otherwise as markdown.
+func (a *app) SendMessage(msg sender.Message) error {
+	return a.SendMessageContext(context.Background(), msg)
+}
+
+func (a *app) SendMessageContext(ctx context.Context, msg sender.Message) error {
+	toUsers := strings.Join(a.c.Receivers, "|")
+	token, err := a.getToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(msg.Links) > 0 {
+		return a.post(ctx, textCardRequest{
+			AccessToken: token,
+			AgentID:     a.c.AgentId,
+			MsgType:     textCardMessageType,
+			ToUser:      toUsers,
+			TextCard:    renderTextCard(msg),
+		})
+	}
+
+	return a.post(ctx, markdownRequest{
 		AccessToken: token,
 		AgentID:     a.c.AgentId,
-		MsgType:     messageType,
+		MsgType:     markdownMessageType,
 		ToUser:      toUsers,
-		Text: textBody{
-			Content: text,
+		Markdown: textBody{
+			Content: renderMarkdown(msg, maxMarkdownSize),
 		},
-	}
-	resp, err := httpc.Do(context.Background(), http.MethodPost, "https://qyapi.weixin.qq.com/cgi-bin/message/send", req)
+	})
+}
+
+func (a *app) post(ctx context.Context, req any) error {
+	resp, err := httpc.Do(ctx, http.MethodPost, sendMessageUrl, req)
 	if err != nil {
 		return err
 	}
@@ -96,13 +168,13 @@ func (a *app) Send(text string) error {
 	return nil
 }
 
-func (a *app) getToken() (string, error) {
+func (a *app) getToken(ctx context.Context) (string, error) {
 	if time.Since(a.token.Expire) <= time.Duration(-30)*time.Second {
 		return a.token.Token, nil
 	}
 
 	// refetch access token
-	resp, err := httpc.Do(context.Background(), http.MethodGet, refreshTokenUrl, struct {
+	resp, err := httpc.Do(ctx, http.MethodGet, refreshTokenUrl, struct {
 		CorpId string `form:"corpid"`
 		Secret string `form:"corpsecret"`
 	}{